@@ -0,0 +1,77 @@
+package dijkstra
+
+import (
+	"slices"
+	"sync"
+)
+
+// Integer is a constraint for integer cost types.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Unsigned is a constraint for unsigned integer cost types.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// CheckedAddAccumulator creates an accumulator adding the weight of each edge to the accumulated cost.
+// An edge whose sum would overflow is treated as impassable, as if its cost were infinite,
+// so paths too expensive to represent are simply not explored.
+// weight : Function to retrieve the weight of an edge, or false if the edge is impassable.
+func CheckedAddAccumulator[K comparable, C Integer](weight func(from, to K) (C, bool)) func(agg C, from, to K) (C, bool) {
+	return func(agg C, from, to K) (C, bool) {
+		w, ok := weight(from, to)
+		if !ok {
+			return agg, false
+		}
+		next := agg + w
+		if (w > 0 && next < agg) || (w < 0 && next > agg) {
+			return agg, false
+		}
+		return next, true
+	}
+}
+
+// Number is a constraint for numeric cost types.
+type Number interface {
+	Integer | ~float32 | ~float64
+}
+
+// WithMemoAccumulator sets the accumulator of the options to add the weight of entering each node
+// to the accumulated cost, calling weight once per node in each search, e.g. for weights that are costly to look up.
+// It takes the options rather than returning a bare accumulator so that the cache is cleared as each search starts,
+// and the weights may change between searches.
+// weight : Function to retrieve the weight of entering a node, or false if the node is impassable.
+// The cache is guarded by a mutex, so ParallelEdges and AllPairsParallel may use it,
+// although a search starting concurrently clears it and weight may then be called again for a node.
+func WithMemoAccumulator[K comparable, C Number](c Options[K, C], weight func(to K) (C, bool)) Options[K, C] {
+	type memo struct {
+		weight C
+		ok     bool
+	}
+	var mu sync.Mutex
+	cache := make(map[K]memo)
+	c.Accumulator = func(agg C, from, to K) (C, bool) {
+		mu.Lock()
+		m, cached := cache[to]
+		mu.Unlock()
+		if !cached {
+			m.weight, m.ok = weight(to)
+			mu.Lock()
+			cache[to] = m
+			mu.Unlock()
+		}
+		if !m.ok {
+			return agg, false
+		}
+		return agg + m.weight, true
+	}
+	c.AccumulatorCtx = nil
+	c.resets = append(slices.Clip(c.resets), func() {
+		mu.Lock()
+		clear(cache)
+		mu.Unlock()
+	})
+	return c
+}
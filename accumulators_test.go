@@ -0,0 +1,136 @@
+package dijkstra_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckedAddAccumulator(t *testing.T) {
+	a := assert.New(t)
+	unsigned := dijkstra.CheckedAddAccumulator(func(from, to int) (uint8, bool) {
+		return uint8(to), to >= 0
+	})
+	next, ok := unsigned(math.MaxUint8-5, 0, 5)
+	a.True(ok)
+	a.Equal(uint8(math.MaxUint8), next)
+	_, ok = unsigned(math.MaxUint8-5, 0, 6)
+	a.False(ok)
+	_, ok = unsigned(0, 0, -1)
+	a.False(ok)
+
+	signed := dijkstra.CheckedAddAccumulator(func(from, to int) (int64, bool) {
+		return int64(to), true
+	})
+	sum, _ := signed(math.MaxInt64-1, 0, 1)
+	a.Equal(int64(math.MaxInt64), sum)
+	_, ok = signed(math.MaxInt64, 0, 1)
+	a.False(ok)
+	sum, _ = signed(math.MinInt64+1, 0, -1)
+	a.Equal(int64(math.MinInt64), sum)
+	_, ok = signed(math.MinInt64, 0, -1)
+	a.False(ok)
+}
+
+func TestCheckedAddAccumulatorSearch(t *testing.T) {
+	a := assert.New(t)
+	weights := map[[2]string]uint8{
+		{"a", "b"}: 200,
+		{"b", "c"}: 100,
+		{"a", "d"}: 50,
+		{"d", "c"}: 50,
+		{"c", "e"}: 200,
+	}
+	options := dijkstra.Options[string, uint8]{
+		Accumulator: dijkstra.CheckedAddAccumulator(func(from, to string) (uint8, bool) {
+			w, ok := weights[[2]string{from, to}]
+			return w, ok
+		}),
+		Less: func(i, j uint8) bool { return i < j },
+		Edges: func(from string) []string {
+			return map[string][]string{"a": {"b", "d"}, "b": {"c"}, "d": {"c"}, "c": {"e"}}[from]
+		},
+	}
+	costs := options.Dijkstra("a", 0)
+	a.Equal(uint8(100), costs["c"].Cost)
+	a.Equal("d", *costs["c"].Prev)
+	_, ok := costs["e"]
+	a.False(ok)
+}
+
+func TestWithMemoAccumulator(t *testing.T) {
+	a := assert.New(t)
+	graph := RandomCostGraph(rand.New(rand.NewSource(1)), 10, 10, 9)
+	options := MockOptions(graph)
+	expected := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	calls := make(map[Key]int)
+	memo := dijkstra.WithMemoAccumulator(options, func(to Key) (Cost, bool) {
+		calls[to]++
+		cost, ok := graph[to]
+		return cost, ok
+	})
+	a.Equal(expected, memo.Dijkstra(Key{X: 0, Y: 0}, Cost(0)))
+	for key, n := range calls {
+		a.Equal(1, n, key)
+	}
+
+	// The cache is cleared as the next search starts.
+	graph[Key{X: 0, Y: 1}] += 10
+	a.Equal(options.Dijkstra(Key{X: 0, Y: 0}, Cost(0)), memo.Dijkstra(Key{X: 0, Y: 0}, Cost(0)))
+	a.NotEqual(expected, memo.Dijkstra(Key{X: 0, Y: 0}, Cost(0)))
+
+	solver := memo.NewSolver()
+	solver.Run(Key{X: 0, Y: 0}, Cost(0))
+	graph[Key{X: 0, Y: 1}] -= 10
+	a.Equal(expected, solver.UpdateEdge(Key{X: 0, Y: 1}))
+}
+
+func TestWithMemoAccumulatorParallel(t *testing.T) {
+	a := assert.New(t)
+	graph := RandomCostGraph(rand.New(rand.NewSource(2)), 10, 10, 9)
+	options := MockOptions(graph)
+	nodes := []Key{{X: 0, Y: 0}, {X: 9, Y: 9}, {X: 4, Y: 5}, {X: 9, Y: 0}}
+	expected := options.AllPairs(nodes, Cost(0))
+	memo := dijkstra.WithMemoAccumulator(options, func(to Key) (Cost, bool) {
+		cost, ok := graph[to]
+		return cost, ok
+	})
+	memo.ParallelEdges = true
+	a.Equal(expected[Key{X: 0, Y: 0}], memo.Dijkstra(Key{X: 0, Y: 0}, Cost(0)))
+	a.Equal(expected, memo.AllPairsParallel(nodes, Cost(0), 4))
+}
+
+func BenchmarkMemoAccumulator(b *testing.B) {
+	graph := FlatGraph(100, 100, 1)
+	options := MockOptions(graph)
+	weight := func(to Key) (Cost, bool) {
+		cost, ok := graph[to]
+		return cost, ok
+	}
+	b.Run("Plain", func(b *testing.B) {
+		calls := 0
+		options.Accumulator = func(agg Cost, from, to Key) (Cost, bool) {
+			calls++
+			cost, ok := weight(to)
+			return agg + cost, ok
+		}
+		for i := 0; i < b.N; i++ {
+			options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+		}
+		b.ReportMetric(float64(calls)/float64(b.N), "weights/op")
+	})
+	b.Run("Memo", func(b *testing.B) {
+		calls := 0
+		memo := dijkstra.WithMemoAccumulator(options, func(to Key) (Cost, bool) {
+			calls++
+			return weight(to)
+		})
+		for i := 0; i < b.N; i++ {
+			memo.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+		}
+		b.ReportMetric(float64(calls)/float64(b.N), "weights/op")
+	})
+}
@@ -0,0 +1,84 @@
+package dijkstra
+
+// DijkstraAllPaths runs Dijkstra's algorithm like Dijkstra,
+// but also records in Node.Prevs every predecessor reaching a node at a cost equal to its own.
+// Costs are compared with Options.Equal.
+func (c Options[K, C]) DijkstraAllPaths(start K, initial C) (costs map[K]Node[K, C]) {
+	return c.prepare().run(query[K, C]{starts: []K{start}, initial: initial, allPrevs: true})
+}
+
+// tie adds the predecessor of the popped node to the settled node if both have equal costs.
+func (c Options[K, C]) tie(settled, popped Node[K, C]) Node[K, C] {
+	if popped.Prev == nil || !c.equal(popped.Cost, settled.Cost) {
+		return settled
+	}
+	for _, prev := range settled.Prevs {
+		if prev == *popped.Prev {
+			return settled
+		}
+	}
+	settled.Prevs = append(settled.Prevs, *popped.Prev)
+	return settled
+}
+
+// AllShortestPaths resolves every path from the start node to the goal node
+// through the predecessors recorded by DijkstraAllPaths.
+// For costs from other searches, only the single path through Node.Prev is resolved.
+// returns : The paths, NotReachableError if the goal is not in the costs,
+// or BrokenPathError or CyclicPathError if no path can be resolved as ShortestPath reports.
+func (c Options[K, C]) AllShortestPaths(costs map[K]Node[K, C], goal K) ([][]K, error) {
+	goal = c.canonical(goal)
+	if _, ok := costs[goal]; !ok {
+		return nil, newNotReachableError(costs, c.Less, goal)
+	}
+	var paths [][]K
+	onPath := make(map[K]bool)
+	// reversed holds the path from the goal back to the current node.
+	var reversed []K
+	var walk func(current, last K) error
+	walk = func(current, last K) error {
+		node, ok := costs[current]
+		if !ok {
+			return &BrokenPathError[K]{Goal: goal, Node: last, Missing: current}
+		}
+		if onPath[current] {
+			return nil
+		}
+		onPath[current] = true
+		reversed = append(reversed, current)
+		defer func() {
+			onPath[current] = false
+			reversed = reversed[:len(reversed)-1]
+		}()
+		prevs := node.Prevs
+		if len(prevs) == 0 && node.Prev != nil {
+			prevs = []K{*node.Prev}
+		}
+		if len(prevs) == 0 {
+			path := make([]K, len(reversed))
+			for i, key := range reversed {
+				path[len(reversed)-1-i] = key
+			}
+			paths = append(paths, path)
+			return nil
+		}
+		for _, prev := range prevs {
+			if err := walk(prev, current); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(goal, goal); err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		// Every predecessor led back onto the path, so the chain through Prev tells the cycle apart.
+		path, err := c.shortestPath(costs, goal, nil)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
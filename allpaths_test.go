@@ -0,0 +1,84 @@
+package dijkstra_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDijkstraAllPathsEqual(t *testing.T) {
+	a := assert.New(t)
+	options := dijkstra.StringGraphOptions(map[string]map[string]float64{
+		"a": {"b": 0.1, "c": 0.3},
+		"b": {"d": 0.2},
+		"c": {"d": 0},
+	})
+	costs := options.DijkstraAllPaths("a", 0)
+	a.Equal([]string{"c"}, costs["d"].Prevs)
+
+	options.Equal = func(x, y float64) bool {
+		return math.Abs(x-y) < 1e-9
+	}
+	costs = options.DijkstraAllPaths("a", 0)
+	a.ElementsMatch([]string{"b", "c"}, costs["d"].Prevs)
+	a.Nil(costs["a"].Prevs)
+}
+
+func TestAllShortestPaths(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  1  1 
+	1  ■  1 
+	1  1  1 
+	`)
+	options := MockOptions(graph)
+	start, goal := Key{X: 0, Y: 0}, Key{X: 2, Y: 2}
+	costs := options.DijkstraAllPaths(start, Cost(0))
+	paths, err := options.AllShortestPaths(costs, goal)
+	a.NoError(err)
+	a.ElementsMatch([][]Key{
+		{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+		{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}},
+	}, paths)
+
+	paths, err = options.AllShortestPaths(options.Dijkstra(start, Cost(0)), goal)
+	a.NoError(err)
+	a.Len(paths, 1)
+
+	_, err = options.AllShortestPaths(costs, Key{X: 1, Y: 1})
+	var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+	a.ErrorAs(err, &notReachableErr)
+}
+
+func TestAllShortestPathsBroken(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(1, 5, 1))
+	costs := options.DijkstraAllPaths(Key{X: 0, Y: 0}, Cost(0))
+	delete(costs, Key{X: 2, Y: 0})
+	_, err := options.AllShortestPaths(costs, Key{X: 4, Y: 0})
+	var brokenErr *dijkstra.BrokenPathError[Key]
+	a.ErrorAs(err, &brokenErr)
+	a.Equal(dijkstra.BrokenPathError[Key]{Goal: Key{X: 4, Y: 0}, Node: Key{X: 3, Y: 0}, Missing: Key{X: 2, Y: 0}}, *brokenErr)
+
+	x, y := "x", "y"
+	cycle := map[string]dijkstra.Node[string, int]{
+		x: {Key: x, Cost: 1, Prev: &y, Prevs: []string{y}},
+		y: {Key: y, Cost: 1, Prev: &x, Prevs: []string{x}},
+	}
+	_, err = WeightedOptions(nil).AllShortestPaths(cycle, x)
+	var cyclicErr *dijkstra.CyclicPathError[string, int]
+	a.ErrorAs(err, &cyclicErr)
+	a.Equal(x, cyclicErr.Node)
+}
+
+func TestAllShortestPathsFlatGrid(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(3, 3, 1))
+	costs := options.DijkstraAllPaths(Key{X: 0, Y: 0}, Cost(0))
+	paths, err := options.AllShortestPaths(costs, Key{X: 2, Y: 2})
+	a.NoError(err)
+	// Choosing 2 of the 4 moves to go down.
+	a.Len(paths, 6)
+}
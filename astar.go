@@ -0,0 +1,61 @@
+package dijkstra
+
+// AStar runs the A* algorithm from the start node until the goal node is settled,
+// ordering the queue by the accumulated cost plus Options.Heuristic.
+// The returned costs hold the true accumulated costs of the settled nodes,
+// which is enough to resolve the path to the goal with ShortestPath.
+// When Heuristic is nil, it is the same as DijkstraTo.
+func (c Options[K, C]) AStar(start, goal K, initial C) (costs map[K]Node[K, C]) {
+	return must(c.prepare().astar(start, goal, initial))
+}
+
+// TryAStar runs the A* algorithm like AStar, but returns errors instead of panicking,
+// such as from Validate or DebugAdmissible.
+func (c Options[K, C]) TryAStar(start, goal K, initial C) (costs map[K]Node[K, C], err error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c.withDefaults().astar(start, goal, initial)
+}
+
+func (c Options[K, C]) astar(start, goal K, initial C) (costs map[K]Node[K, C], err error) {
+	goal = c.canonical(goal)
+	q := query[K, C]{starts: []K{start}, initial: initial, stop: func(node Node[K, C]) bool {
+		return node.Key == goal
+	}}
+	debug := c.DebugAdmissible && c.Heuristic != nil
+	if debug && c.ReverseEdges == nil {
+		return nil, ErrNoReverseEdges
+	}
+	// estimated holds the nodes the heuristic was called with in order, to check it when debugging.
+	var estimated []K
+	seen := make(map[K]struct{})
+	if c.Heuristic != nil {
+		q.estimate = func(key K) C {
+			if debug {
+				if _, ok := seen[key]; !ok {
+					seen[key] = struct{}{}
+					estimated = append(estimated, key)
+				}
+			}
+			return c.Heuristic(key, goal)
+		}
+	}
+	costs, err = c.try(q)
+	if err != nil || !debug {
+		return costs, err
+	}
+	check := c
+	check.OnFinalize, check.OnRelax, check.MaxNodes = nil, nil, 0
+	actual := check.DijkstraReverse(goal, initial)
+	for _, key := range estimated {
+		node, ok := actual[key]
+		if !ok {
+			continue
+		}
+		if estimate := c.Heuristic(key, goal); c.Less(node.Cost, c.Add(initial, estimate)) {
+			return costs, &InadmissibleHeuristicError[K, C]{Node: key, Estimate: estimate, Actual: node.Cost}
+		}
+	}
+	return costs, nil
+}
@@ -0,0 +1,168 @@
+package dijkstra_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAStar(t *testing.T) {
+	a := assert.New(t)
+	rnd := rand.New(rand.NewSource(2))
+	// settled and total count the nodes settled by AStar and by Dijkstra over the graphs.
+	settled, total := 0, 0
+	for i := 0; i < 20; i++ {
+		graph := RandomCostGraph(rnd, 30, 30, 9)
+		start, goal := Key{X: 0, Y: 0}, Key{X: 29, Y: 29}
+		graph[start], graph[goal] = 1, 1
+		options := MockOptions(graph)
+		expected := options.Dijkstra(start, Cost(0))
+		if _, ok := expected[goal]; !ok {
+			continue
+		}
+		options.Add = func(g, h Cost) Cost { return g + h }
+		options.Heuristic = Manhattan
+		costs := options.AStar(start, goal, Cost(0))
+		a.LessOrEqual(len(costs), len(expected))
+		settled, total = settled+len(costs), total+len(expected)
+		a.Equal(expected[goal].Cost, costs[goal].Cost)
+		for key, node := range costs {
+			a.Equal(expected[key].Cost, node.Cost, key)
+		}
+		path := lo.Must(options.ShortestPath(costs, goal))
+		a.Equal(expected[goal].Cost, PathCost(graph, path))
+	}
+	a.Less(settled, total)
+
+	// On a flat graph, only the nodes along the straight line to the goal are settled.
+	options := MockOptions(FlatGraph(30, 30, 1))
+	options.Add = func(g, h Cost) Cost { return g + h }
+	options.Heuristic = Manhattan
+	start, goal := Key{X: 0, Y: 0}, Key{X: 0, Y: 15}
+	costs := options.AStar(start, goal, Cost(0))
+	a.Equal(Cost(15), costs[goal].Cost)
+	a.Less(2*len(costs), len(options.DijkstraTo(start, goal, Cost(0))))
+}
+
+func TestAStarWithoutHeuristic(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(5, 5, 1))
+	costs := options.AStar(Key{X: 0, Y: 0}, Key{X: 4, Y: 4}, Cost(0))
+	a.Equal(Cost(8), costs[Key{X: 4, Y: 4}].Cost)
+}
+
+func TestAStarReopenClosed(t *testing.T) {
+	a := assert.New(t)
+	options := WeightedOptions(map[string]map[string]int{
+		"s": {"a": 1, "b": 3},
+		"a": {"b": 1},
+		"b": {"g": 3},
+	})
+	// Admissible but inconsistent, as h(a) > w(a, b) + h(b).
+	estimates := map[string]int{"a": 4}
+	options.Add = func(g, h int) int { return g + h }
+	options.Heuristic = func(from, goal string) int {
+		return estimates[from]
+	}
+	costs := options.AStar("s", "g", 0)
+	a.Equal(6, costs["g"].Cost)
+
+	options.ReopenClosed = true
+	costs = options.AStar("s", "g", 0)
+	a.Equal(5, costs["g"].Cost)
+	a.Equal([]string{"s", "a", "b", "g"}, lo.Must(options.ShortestPath(costs, "g")))
+}
+
+func TestDebugAdmissible(t *testing.T) {
+	a := assert.New(t)
+	options := WeightedOptions(map[string]map[string]int{
+		"s": {"a": 1, "b": 1},
+		"a": {"s": 1, "g": 1},
+		"b": {"s": 1, "g": 5},
+		"g": {"a": 1, "b": 5},
+	})
+	options.Add = func(g, h int) int { return g + h }
+	estimates := map[string]int{"a": 10}
+	options.Heuristic = func(from, goal string) int {
+		return estimates[from]
+	}
+	// The overestimate at a hides the shortest path.
+	a.Equal(6, options.AStar("s", "g", 0)["g"].Cost)
+	_, err := options.TryAStar("s", "g", 0)
+	a.NoError(err)
+
+	options.DebugAdmissible = true
+	_, err = options.TryAStar("s", "g", 0)
+	a.ErrorIs(err, dijkstra.ErrNoReverseEdges)
+	options.ReverseEdges = options.Edges
+	_, err = options.TryAStar("s", "g", 0)
+	var inadmissibleErr *dijkstra.InadmissibleHeuristicError[string, int]
+	a.ErrorAs(err, &inadmissibleErr)
+	a.Equal(dijkstra.InadmissibleHeuristicError[string, int]{Node: "a", Estimate: 10, Actual: 1}, *inadmissibleErr)
+	a.Panics(func() {
+		options.AStar("s", "g", 0)
+	})
+
+	estimates["a"] = 1
+	costs, err := options.TryAStar("s", "g", 0)
+	a.NoError(err)
+	a.Equal(2, costs["g"].Cost)
+}
+
+func TestDebugAdmissibleDirected(t *testing.T) {
+	a := assert.New(t)
+	options := WeightedOptions(map[string]map[string]int{
+		"s": {"b": 1, "g": 5},
+		"g": {"b": 1},
+	})
+	options.Add = func(g, h int) int { return g + h }
+	// b has no path to g, so no estimate of it is an overestimate.
+	estimates := map[string]int{"b": 100}
+	options.Heuristic = func(from, goal string) int {
+		return estimates[from]
+	}
+	options.DebugAdmissible = true
+	reverse := map[string][]string{"b": {"s", "g"}, "g": {"s"}}
+	options.ReverseEdges = func(to string) []string {
+		return reverse[to]
+	}
+	costs, err := options.TryAStar("s", "g", 0)
+	a.NoError(err)
+	a.Equal(5, costs["g"].Cost)
+
+	estimates["s"] = 6
+	_, err = options.TryAStar("s", "g", 0)
+	var inadmissibleErr *dijkstra.InadmissibleHeuristicError[string, int]
+	a.ErrorAs(err, &inadmissibleErr)
+	a.Equal(dijkstra.InadmissibleHeuristicError[string, int]{Node: "s", Estimate: 6, Actual: 5}, *inadmissibleErr)
+}
+
+func TestDebugAdmissibleNodePenalty(t *testing.T) {
+	a := assert.New(t)
+	options := WeightedOptions(map[string]map[string]int{
+		"s": {"a": 1, "b": 1},
+		"a": {"s": 1, "g": 1},
+		"b": {"s": 1, "g": 5},
+		"g": {"a": 1, "b": 5},
+	})
+	options.Add = func(g, h int) int { return g + h }
+	options.NodePenalty = func(node string) int {
+		if node == "g" {
+			return 2
+		}
+		return 0
+	}
+	// The estimates are exact once the penalty of entering g is counted.
+	estimates := map[string]int{"s": 4, "a": 3, "b": 5}
+	options.Heuristic = func(from, goal string) int {
+		return estimates[from]
+	}
+	options.DebugAdmissible = true
+	options.ReverseEdges = options.Edges
+	costs, err := options.TryAStar("s", "g", 0)
+	a.NoError(err)
+	a.Equal(4, costs["g"].Cost)
+}
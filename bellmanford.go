@@ -0,0 +1,122 @@
+package dijkstra
+
+import (
+	"context"
+	"fmt"
+)
+
+// BellmanFord runs the Bellman-Ford algorithm, which unlike Dijkstra supports edges lowering the cost.
+// It takes O(V·E) time, so use it only when the costs are not monotonic.
+// nodes : The nodes of the graph, whose edges are relaxed in order.
+// The nodes reached outside of them are relaxed after them, in the order they were discovered.
+// returns : The costs, which ShortestPath resolves paths against, all finalized unless there is a negative cycle,
+// or NegativeCycleError if a cycle reachable from the start node keeps lowering the costs.
+func BellmanFord[K comparable, C any](
+	start K,
+	accumulator func(agg C, from, to K) (next C, ok bool),
+	initial C,
+	less func(i C, j C) bool,
+	edges func(from K) (dest []K),
+	nodes []K,
+) (costs map[K]Node[K, C], err error) {
+	return Options[K, C]{
+		Accumulator: accumulator,
+		Less:        less,
+		Edges:       edges,
+	}.BellmanFord(start, initial, nodes)
+}
+
+// BellmanFord runs the Bellman-Ford algorithm with the accumulator, the comparison and the edges of the options,
+// like the package-level BellmanFord.
+func (c Options[K, C]) BellmanFord(start K, initial C, nodes []K) (costs map[K]Node[K, C], err error) {
+	c = c.prepare()
+	c.begin()
+	start = c.canonical(start)
+	costs = map[K]Node[K, C]{start: {Key: start, Cost: initial}}
+	// order holds the nodes whose edges are relaxed, the given nodes followed by the start and
+	// the nodes reached outside of them in the order they were discovered.
+	order := make([]K, 0, len(nodes)+1)
+	known := make(map[K]struct{}, len(nodes)+1)
+	discover := func(key K) {
+		if _, ok := known[key]; !ok {
+			known[key] = struct{}{}
+			order = append(order, key)
+		}
+	}
+	for _, key := range nodes {
+		discover(c.canonical(key))
+	}
+	discover(start)
+	relax := func() (changed K, ok bool, err error) {
+		for i := 0; i < len(order); i++ {
+			from := order[i]
+			node, reached := costs[from]
+			if !reached {
+				continue
+			}
+			err := c.neighbors(context.Background(), from, node.Cost, func(to K, next C) {
+				if dest, reached := costs[to]; reached && !c.Less(next, dest.Cost) {
+					return
+				}
+				discover(to)
+				costs[to] = Node[K, C]{Key: to, Cost: next, Prev: &from}
+				changed, ok = to, true
+			})
+			if err != nil {
+				return changed, false, err
+			}
+		}
+		return changed, ok, nil
+	}
+	// finalize marks the costs as final once no edge lowers them anymore.
+	finalize := func() map[K]Node[K, C] {
+		for key, node := range costs {
+			node.Finalized = true
+			costs[key] = node
+		}
+		return costs
+	}
+	// The rounds are counted against the nodes discovered so far, which only grow.
+	for i := 1; i < len(order); i++ {
+		_, ok, err := relax()
+		if err != nil {
+			return costs, err
+		}
+		if !ok {
+			return finalize(), nil
+		}
+	}
+	changed, ok, err := relax()
+	if err != nil {
+		return costs, err
+	}
+	if !ok {
+		return finalize(), nil
+	}
+	// Walking back from a node still lowered after V-1 rounds leads into the cycle within V steps.
+	for range order {
+		changed = *costs[changed].Prev
+	}
+	cycle := []K{changed}
+	for current := *costs[changed].Prev; current != changed; current = *costs[current].Prev {
+		cycle = append(cycle, current)
+	}
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+	return costs, &NegativeCycleError[K, C]{Costs: costs, Cycle: cycle}
+}
+
+var _ error = &NegativeCycleError[int, int]{}
+
+// NegativeCycleError indicates that a cycle reachable from the start node lowers the costs
+// every time it is followed, so the nodes it reaches have no shortest path.
+type NegativeCycleError[K comparable, C any] struct {
+	Costs map[K]Node[K, C]
+	// Cycle lists the nodes of the cycle in the order of its edges.
+	Cycle []K
+}
+
+func (e *NegativeCycleError[K, C]) Error() string {
+	return fmt.Sprintf("the graph has a cycle lowering the costs: %v", e.Cycle)
+}
@@ -0,0 +1,55 @@
+package dijkstra_test
+
+import (
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBellmanFord(t *testing.T) {
+	a := assert.New(t)
+	weights := map[string]map[string]int{
+		"a": {"b": 1, "c": 5},
+		"c": {"b": -10},
+		"b": {"d": 1},
+	}
+	options := WeightedOptions(weights)
+	nodes := []string{"a", "b", "c", "d"}
+	costs, err := dijkstra.BellmanFord("a", options.Accumulator, 0, options.Less, options.Edges, nodes)
+	a.NoError(err)
+	a.Equal(-5, costs["b"].Cost)
+	a.Equal(-4, costs["d"].Cost)
+	a.Equal([]string{"a", "c", "b", "d"}, lo.Must(options.PathResolve(costs, "d")))
+	// Dijkstra settles b before finding the cheaper path through c.
+	a.Equal(2, options.Dijkstra("a", 0)["d"].Cost)
+
+	weights["d"] = map[string]int{"c": 1}
+	costs, err = options.BellmanFord("a", 0, nodes)
+	var cycleErr *dijkstra.NegativeCycleError[string, int]
+	a.ErrorAs(err, &cycleErr)
+	a.ElementsMatch([]string{"b", "c", "d"}, cycleErr.Cycle)
+	a.Equal(costs, cycleErr.Costs)
+}
+
+func TestBellmanFordUnlistedNodes(t *testing.T) {
+	a := assert.New(t)
+	weights := map[string]map[string]int{
+		"a": {"b": 1, "c": 5},
+		"c": {"b": -10},
+		"b": {"d": 1},
+	}
+	options := WeightedOptions(weights)
+	// The nodes reached outside of the listed ones are relaxed too.
+	costs, err := options.BellmanFord("a", 0, []string{"d"})
+	a.NoError(err)
+	a.Equal(-4, costs["d"].Cost)
+	a.Equal([]string{"a", "c", "b", "d"}, lo.Must(options.PathResolve(costs, "d")))
+
+	weights["d"] = map[string]int{"c": 1}
+	_, err = options.BellmanFord("a", 0, nil)
+	var cycleErr *dijkstra.NegativeCycleError[string, int]
+	a.ErrorAs(err, &cycleErr)
+	a.ElementsMatch([]string{"b", "c", "d"}, cycleErr.Cycle)
+}
@@ -0,0 +1,195 @@
+package dijkstra
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// ErrNoReverseEdges is returned by backward searches when Options.ReverseEdges is not set,
+// or when neither it nor Edges is set for the searches assuming a symmetric graph.
+var ErrNoReverseEdges = errors.New("ReverseEdges is required to search backwards from the goal")
+
+// BidirectionalAStar finds the shortest path from start to goal by running A* from both ends
+// until the two searches meet.
+// Both halves are joined with combine, so initial must be its identity (e.g. 0 for additive costs).
+// heuristic : Function to estimate the cost from a to b.
+// It must be consistent, heuristic(a, b) <= cost(a -> n) + heuristic(n, b) for every edge a -> n,
+// otherwise the returned path may not be the shortest.
+// combine : Function to add an estimated cost to an accumulated cost.
+// Blocked, StartValid, NewQueue and NodePenalty apply as in Dijkstra, and MaxNodes bounds the nodes settled by both halves.
+// returns : The path, its cost, and an error if the goal is not reachable,
+// or LimitReachedError with the costs of the forward half if MaxNodes is reached first.
+func (c Options[K, C]) BidirectionalAStar(
+	start, goal K,
+	initial C,
+	heuristic func(a, b K) C,
+	combine func(g, h C) C,
+) ([]K, C, error) {
+	var cost C
+	c = c.prepare()
+	start, goal = c.canonical(start), c.canonical(goal)
+	if c.ReverseEdges == nil {
+		return nil, cost, ErrNoReverseEdges
+	}
+	reverse, err := c.reversed()
+	if err != nil {
+		return nil, cost, err
+	}
+	if c.StartValid != nil && !c.StartValid(start) {
+		return nil, cost, &NotReachableError[K, C]{Costs: map[K]Node[K, C]{}, Start: start, Goal: goal, StartingUnknown: true, Reason: StartInvalid}
+	}
+	if c.Blocked != nil && (c.Blocked(start) || c.Blocked(goal)) {
+		return nil, cost, newNotReachableError(map[K]Node[K, C]{}, c.Less, goal)
+	}
+	c.begin()
+	forward := newHalfSearch(c, c.neighbors, func(key K, g C) C {
+		return combine(g, heuristic(key, goal))
+	})
+	backward := newHalfSearch(c, reverse.neighbors, func(key K, g C) C {
+		return combine(g, heuristic(start, key))
+	})
+
+	var meet K
+	found := false
+	// join records a meeting point whenever a node becomes known to both searches.
+	join := func(key K, g C, other *halfSearch[K, C]) {
+		o, ok := other.best[key]
+		if !ok {
+			return
+		}
+		if total := combine(g, o.Cost); !found || c.Less(total, cost) {
+			meet, cost, found = key, total, true
+		}
+	}
+	forward.seed(start, initial)
+	backward.seed(goal, initial)
+	join(goal, initial, forward)
+
+	for !forward.empty() && !backward.empty() {
+		// With consistent heuristics, the smallest estimate of either side is a lower bound
+		// of every path not yet found, so the search is done once it reaches the best path.
+		if found && (!c.Less(forward.peek(), cost) || !c.Less(backward.peek(), cost)) {
+			break
+		}
+		if c.MaxNodes > 0 && len(forward.closed)+len(backward.closed) >= c.MaxNodes {
+			return nil, cost, &LimitReachedError[K, C]{Costs: forward.best, Limit: c.MaxNodes}
+		}
+		side, other := forward, backward
+		if c.Less(backward.peek(), forward.peek()) {
+			side, other = backward, forward
+		}
+		err := side.expand(func(key K, g C) {
+			join(key, g, other)
+		})
+		if err != nil {
+			return nil, cost, err
+		}
+	}
+	if !found {
+		return nil, cost, newNotReachableError(forward.best, c.Less, goal)
+	}
+
+	// The path is walked back from the meeting node to the start, then reversed once.
+	path := []K{meet}
+	for node := forward.best[meet]; node.Prev != nil; node = forward.best[*node.Prev] {
+		path = append(path, *node.Prev)
+	}
+	slices.Reverse(path)
+	for node := backward.best[meet]; node.Prev != nil; node = backward.best[*node.Prev] {
+		path = append(path, *node.Prev)
+	}
+	return path, cost, nil
+}
+
+// halfSearch is one direction of a bidirectional search.
+type halfSearch[K comparable, C any] struct {
+	open PriorityQueue[K, C]
+	// head is the node popped from open to peek at its priority, if any, popped next.
+	head      *Node[K, C]
+	best      map[K]Node[K, C]
+	closed    map[K]struct{}
+	less      func(i, j C) bool
+	blocked   func(node K) bool
+	neighbors func(ctx context.Context, from K, agg C, visit func(to K, cost C)) error
+	priority  func(key K, g C) C
+}
+
+func newHalfSearch[K comparable, C any](
+	c Options[K, C],
+	neighbors func(ctx context.Context, from K, agg C, visit func(to K, cost C)) error,
+	priority func(key K, g C) C,
+) *halfSearch[K, C] {
+	var open PriorityQueue[K, C]
+	if c.NewQueue != nil {
+		open = c.NewQueue(c.Less)
+	} else {
+		open = newPriorityNodes[K](c.Less, 0)
+	}
+	return &halfSearch[K, C]{
+		open:      open,
+		best:      make(map[K]Node[K, C]),
+		closed:    make(map[K]struct{}),
+		less:      c.Less,
+		blocked:   c.Blocked,
+		neighbors: neighbors,
+		priority:  priority,
+	}
+}
+
+func (s *halfSearch[K, C]) seed(key K, initial C) {
+	node := Node[K, C]{Key: key, Cost: initial}
+	s.best[key] = node
+	s.open.Push(node, s.priority(key, initial))
+}
+
+func (s *halfSearch[K, C]) empty() bool {
+	return s.head == nil && s.open.Empty()
+}
+
+// peek returns the priority of the node popped next.
+func (s *halfSearch[K, C]) peek() C {
+	if s.head == nil {
+		node := s.open.Pop()
+		s.head = &node
+	}
+	return s.priority(s.head.Key, s.head.Cost)
+}
+
+func (s *halfSearch[K, C]) pop() Node[K, C] {
+	if s.head == nil {
+		return s.open.Pop()
+	}
+	node := *s.head
+	s.head = nil
+	return node
+}
+
+// expand closes the next node and relaxes its edges, reporting every improved node.
+func (s *halfSearch[K, C]) expand(improved func(key K, g C)) error {
+	node := s.pop()
+	if _, ok := s.closed[node.Key]; ok {
+		return nil
+	}
+	s.closed[node.Key] = struct{}{}
+	// The first node popped for a key is the best one, which is final once closed.
+	best := s.best[node.Key]
+	best.Finalized = true
+	s.best[node.Key] = best
+	current := node.Key
+	return s.neighbors(context.Background(), current, node.Cost, func(next K, g C) {
+		if _, ok := s.closed[next]; ok {
+			return
+		}
+		if s.blocked != nil && s.blocked(next) {
+			return
+		}
+		if b, ok := s.best[next]; ok && !s.less(g, b.Cost) {
+			return
+		}
+		n := Node[K, C]{Key: next, Cost: g, Prev: &current}
+		s.best[next] = n
+		s.open.Push(n, s.priority(next, g))
+		improved(next, g)
+	})
+}
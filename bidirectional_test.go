@@ -0,0 +1,146 @@
+package dijkstra_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBidirectionalAStar(t *testing.T) {
+	a := assert.New(t)
+	rnd := rand.New(rand.NewSource(1))
+	add := func(g, h Cost) Cost { return g + h }
+	for i := 0; i < 50; i++ {
+		graph := RandomCostGraph(rnd, 20, 20, 9)
+		options := MockOptions(graph)
+		options.ReverseEdges = options.Edges
+		start, goal := Key{X: 0, Y: 0}, Key{X: 19, Y: 19}
+		graph[start], graph[goal] = 1, 1
+		costs := options.Dijkstra(start, Cost(0))
+		path, cost, err := options.BidirectionalAStar(start, goal, Cost(0), Manhattan, add)
+		expected, ok := costs[goal]
+		if !ok {
+			var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+			a.ErrorAs(err, &notReachableErr)
+			continue
+		}
+		a.NoError(err)
+		a.Equal(expected.Cost, cost)
+		a.Equal(start, path[0])
+		a.Equal(goal, path[len(path)-1])
+		a.Equal(cost, PathCost(graph, path))
+	}
+}
+
+func TestBidirectionalAStarWeighted(t *testing.T) {
+	a := assert.New(t)
+	graph := RandomCostGraph(rand.New(rand.NewSource(2)), 15, 15, 9)
+	start, goal := Key{X: 0, Y: 0}, Key{X: 14, Y: 14}
+	graph[start], graph[goal] = 1, 1
+	options := MockOptions(graph)
+	weighted := dijkstra.Options[Key, Cost]{
+		Less: options.Less,
+		Add:  func(agg, weight Cost) Cost { return agg + weight },
+		WeightedEdges: func(from Key) []dijkstra.Edge[Key, Cost] {
+			return lo.Map(options.Edges(from), func(to Key, _ int) dijkstra.Edge[Key, Cost] {
+				return dijkstra.Edge[Key, Cost]{To: to, Weight: graph[to]}
+			})
+		},
+		ReverseEdges: options.Edges,
+	}
+	path, cost, err := weighted.BidirectionalAStar(start, goal, Cost(0), Manhattan, func(g, h Cost) Cost { return g + h })
+	a.NoError(err)
+	a.Equal(options.Dijkstra(start, Cost(0))[goal].Cost, cost)
+	a.Equal(start, path[0])
+	a.Equal(goal, path[len(path)-1])
+	a.Equal(cost, PathCost(graph, path))
+}
+
+func TestBidirectionalAStarSameNode(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(3, 3, 1))
+	options.ReverseEdges = options.Edges
+	path, cost, err := options.BidirectionalAStar(Key{X: 1, Y: 1}, Key{X: 1, Y: 1}, Cost(0), Manhattan, func(g, h Cost) Cost { return g + h })
+	a.NoError(err)
+	a.Equal([]Key{{X: 1, Y: 1}}, path)
+	a.Equal(Cost(0), cost)
+}
+
+func TestBidirectionalAStarNoReverseEdges(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(3, 3, 1))
+	_, _, err := options.BidirectionalAStar(Key{X: 0, Y: 0}, Key{X: 2, Y: 2}, Cost(0), Manhattan, func(g, h Cost) Cost { return g + h })
+	a.ErrorIs(err, dijkstra.ErrNoReverseEdges)
+}
+
+func TestBidirectionalAStarOptions(t *testing.T) {
+	a := assert.New(t)
+	options := WeightedOptions(map[string]map[string]int{
+		"a": {"b": 1, "d": 5},
+		"b": {"c": 1},
+		"d": {"c": 5},
+	})
+	options.Add = func(agg, weight int) int { return agg + weight }
+	options.ReverseEdges = func(to string) []string {
+		return map[string][]string{"b": {"a"}, "c": {"b", "d"}, "d": {"a"}}[to]
+	}
+	zero := func(a, b string) int { return 0 }
+	add := func(g, h int) int { return g + h }
+
+	options.NodePenalty = func(node string) int {
+		return lo.Ternary(node == "c", 10, 0)
+	}
+	path, cost, err := options.BidirectionalAStar("a", "c", 0, zero, add)
+	a.NoError(err)
+	a.Equal(options.Dijkstra("a", 0)["c"].Cost, cost)
+	a.Equal(12, cost)
+	a.Equal([]string{"a", "b", "c"}, path)
+	options.NodePenalty = nil
+
+	options.Blocked = func(node string) bool { return node == "b" }
+	path, cost, err = options.BidirectionalAStar("a", "c", 0, zero, add)
+	a.NoError(err)
+	a.Equal(10, cost)
+	a.Equal([]string{"a", "d", "c"}, path)
+	_, _, err = options.BidirectionalAStar("a", "b", 0, zero, add)
+	var notReachableErr *dijkstra.NotReachableError[string, int]
+	a.ErrorAs(err, &notReachableErr)
+	options.Blocked = nil
+
+	options.StartValid = func(start string) bool { return start != "a" }
+	_, _, err = options.BidirectionalAStar("a", "c", 0, zero, add)
+	a.ErrorAs(err, &notReachableErr)
+	a.Equal(dijkstra.StartInvalid, notReachableErr.Reason)
+	options.StartValid = nil
+
+	options.MaxNodes = 1
+	_, _, err = options.BidirectionalAStar("a", "c", 0, zero, add)
+	var limitErr *dijkstra.LimitReachedError[string, int]
+	a.ErrorAs(err, &limitErr)
+	options.MaxNodes = 0
+
+}
+
+func TestBidirectionalAStarNewQueue(t *testing.T) {
+	a := assert.New(t)
+	graph := RandomCostGraph(rand.New(rand.NewSource(3)), 15, 15, 9)
+	start, goal := Key{X: 0, Y: 0}, Key{X: 14, Y: 14}
+	graph[start], graph[goal] = 1, 1
+	options := MockOptions(graph)
+	options.ReverseEdges = options.Edges
+	add := func(g, h Cost) Cost { return g + h }
+	expected, err := options.TryDijkstra(start, Cost(0))
+	a.NoError(err)
+	queues := 0
+	options.NewQueue = func(less func(i, j Cost) bool) dijkstra.PriorityQueue[Key, Cost] {
+		queues++
+		return dijkstra.NewBucketQueue[Key, Cost](less)
+	}
+	_, cost, err := options.BidirectionalAStar(start, goal, Cost(0), Manhattan, add)
+	a.Equal(2, queues)
+	a.NoError(err)
+	a.Equal(expected[goal].Cost, cost)
+}
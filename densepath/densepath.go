@@ -0,0 +1,154 @@
+// Package densepath runs Dijkstra's algorithm on graphs whose nodes are the integers 0 to n-1,
+// keeping the costs in slices instead of maps.
+package densepath
+
+import "github.com/naycoma/dijkstra"
+
+// Costs holds the costs of the nodes of a graph, indexed by node.
+type Costs[C dijkstra.Number] struct {
+	Start int
+	// Cost holds the cost of each reached node, and the zero value for the others.
+	Cost []C
+	// Prev holds the predecessor of each reached node, and -1 for the start node and the others.
+	Prev []int
+	// Reached holds whether each node is reachable from the start node.
+	Reached []bool
+}
+
+// Dijkstra runs Dijkstra's algorithm from the start node on the graph of n nodes,
+// with the same semantics as dijkstra.Options.Dijkstra.
+// Costs are the accumulated costs plus the weights of the edges, which must not be negative.
+// It panics if start is not in [0, n).
+// edges : Function to retrieve the nodes adjacent to a node.
+// weight : Function to retrieve the weight of the edge between two adjacent nodes.
+func Dijkstra[C dijkstra.Number](
+	n, start int,
+	initial C,
+	less func(i, j C) bool,
+	edges func(from int) []int,
+	weight func(from, to int) C,
+) *Costs[C] {
+	costs := &Costs[C]{
+		Start:   start,
+		Cost:    make([]C, n),
+		Prev:    make([]int, n),
+		Reached: make([]bool, n),
+	}
+	for i := range costs.Prev {
+		costs.Prev[i] = -1
+	}
+	settled := make([]bool, n)
+	queue := newQueue(n, costs.Cost, less)
+	costs.Cost[start], costs.Reached[start] = initial, true
+	queue.push(start)
+	for queue.len() > 0 {
+		current := queue.pop()
+		settled[current] = true
+		for _, to := range edges(current) {
+			if settled[to] {
+				continue
+			}
+			next := costs.Cost[current] + weight(current, to)
+			if !costs.Reached[to] {
+				costs.Cost[to], costs.Prev[to], costs.Reached[to] = next, current, true
+				queue.push(to)
+			} else if less(next, costs.Cost[to]) {
+				costs.Cost[to], costs.Prev[to] = next, current
+				queue.fix(to)
+			}
+		}
+	}
+	return costs
+}
+
+// Path resolves the path from the start node to the goal node.
+// returns : The path, or dijkstra.NotReachableError if the goal is not reachable.
+func (c *Costs[C]) Path(goal int) ([]int, error) {
+	if goal < 0 || goal >= len(c.Reached) || !c.Reached[goal] {
+		return nil, &dijkstra.NotReachableError[int, C]{Start: c.Start, Goal: goal}
+	}
+	length := 0
+	for node := goal; node != -1; node = c.Prev[node] {
+		length++
+	}
+	path := make([]int, length)
+	for i, node := length-1, goal; i >= 0; i, node = i-1, c.Prev[node] {
+		path[i] = node
+	}
+	return path, nil
+}
+
+// queue is a binary heap of nodes ordered by their costs, supporting decrease-key.
+type queue[C any] struct {
+	nodes []int
+	// index holds the position of each queued node in nodes.
+	index []int
+	costs []C
+	less  func(i, j C) bool
+}
+
+func newQueue[C any](n int, costs []C, less func(i, j C) bool) *queue[C] {
+	return &queue[C]{index: make([]int, n), costs: costs, less: less}
+}
+
+func (q *queue[C]) len() int {
+	return len(q.nodes)
+}
+
+func (q *queue[C]) push(node int) {
+	q.index[node] = len(q.nodes)
+	q.nodes = append(q.nodes, node)
+	q.up(len(q.nodes) - 1)
+}
+
+func (q *queue[C]) pop() int {
+	top := q.nodes[0]
+	last := len(q.nodes) - 1
+	q.swap(0, last)
+	q.nodes = q.nodes[:last]
+	q.down(0)
+	return top
+}
+
+// fix restores the order after the cost of the queued node was lowered.
+func (q *queue[C]) fix(node int) {
+	q.up(q.index[node])
+}
+
+func (q *queue[C]) lessAt(i, j int) bool {
+	return q.less(q.costs[q.nodes[i]], q.costs[q.nodes[j]])
+}
+
+func (q *queue[C]) swap(i, j int) {
+	q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i]
+	q.index[q.nodes[i]] = i
+	q.index[q.nodes[j]] = j
+}
+
+func (q *queue[C]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.lessAt(i, parent) {
+			return
+		}
+		q.swap(i, parent)
+		i = parent
+	}
+}
+
+func (q *queue[C]) down(i int) {
+	for {
+		smallest := i
+		if left := 2*i + 1; left < len(q.nodes) && q.lessAt(left, smallest) {
+			smallest = left
+		}
+		if right := 2*i + 2; right < len(q.nodes) && q.lessAt(right, smallest) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		q.swap(i, smallest)
+		i = smallest
+	}
+}
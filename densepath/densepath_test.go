@@ -0,0 +1,113 @@
+package densepath_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/naycoma/dijkstra/densepath"
+	"github.com/stretchr/testify/assert"
+)
+
+// grid is a graph of width*height cells numbered row by row, costing weights[to] to enter.
+type grid struct {
+	width, height int
+	weights       []int
+}
+
+func newGrid(rnd *rand.Rand, width, height int) grid {
+	weights := make([]int, width*height)
+	for i := range weights {
+		weights[i] = rnd.Intn(9) + 1
+	}
+	return grid{width: width, height: height, weights: weights}
+}
+
+func (g grid) edges(from int) []int {
+	x, y := from%g.width, from/g.width
+	edges := make([]int, 0, 4)
+	if x > 0 {
+		edges = append(edges, from-1)
+	}
+	if x < g.width-1 {
+		edges = append(edges, from+1)
+	}
+	if y > 0 {
+		edges = append(edges, from-g.width)
+	}
+	if y < g.height-1 {
+		edges = append(edges, from+g.width)
+	}
+	return edges
+}
+
+func (g grid) weight(from, to int) int {
+	return g.weights[to]
+}
+
+func (g grid) options() dijkstra.Options[int, int] {
+	return dijkstra.Options[int, int]{
+		Accumulator: func(agg int, from, to int) (int, bool) {
+			return agg + g.weight(from, to), true
+		},
+		Less:  dijkstra.Ascending[int](),
+		Edges: g.edges,
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	a := assert.New(t)
+	g := newGrid(rand.New(rand.NewSource(1)), 20, 15)
+	// The last row is cut off from the rest.
+	edges := func(from int) []int {
+		var dest []int
+		for _, to := range g.edges(from) {
+			if (from < 280) == (to < 280) {
+				dest = append(dest, to)
+			}
+		}
+		return dest
+	}
+	costs := densepath.Dijkstra(g.width*g.height, 0, 0, dijkstra.Ascending[int](), edges, g.weight)
+	options := g.options()
+	options.Edges = edges
+	expected := options.Dijkstra(0, 0)
+	for node := range g.weights {
+		want, ok := expected[node]
+		a.Equal(ok, costs.Reached[node], node)
+		a.Equal(want.Cost, costs.Cost[node], node)
+		if !ok {
+			_, err := costs.Path(node)
+			var notReachableErr *dijkstra.NotReachableError[int, int]
+			a.ErrorAs(err, &notReachableErr)
+			continue
+		}
+		path, err := costs.Path(node)
+		a.NoError(err)
+		a.Equal(0, path[0])
+		a.Equal(node, path[len(path)-1])
+		total := 0
+		for _, step := range path[1:] {
+			total += g.weights[step]
+		}
+		a.Equal(want.Cost, total)
+	}
+}
+
+func BenchmarkDijkstra(b *testing.B) {
+	// A grid of 100k nodes.
+	g := newGrid(rand.New(rand.NewSource(1)), 400, 250)
+	b.Run("Dense", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			densepath.Dijkstra(g.width*g.height, 0, 0, dijkstra.Ascending[int](), g.edges, g.weight)
+		}
+	})
+	b.Run("Generic", func(b *testing.B) {
+		b.ReportAllocs()
+		options := g.options()
+		for i := 0; i < b.N; i++ {
+			options.Dijkstra(0, 0)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package dijkstra
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// UnreachableReason explains why a goal could not be reached.
+type UnreachableReason int
+
+const (
+	// ReasonUnknown means that no diagnosis was made.
+	ReasonUnknown UnreachableReason = iota
+	// StartIsolated means that no edge from the start node was passable.
+	StartIsolated
+	// GoalNeverExpanded means that the goal was never returned by the edges of any reached node.
+	GoalNeverExpanded
+	// GoalBlocked means that the goal was returned by the edges, but the accumulator rejected every edge into it.
+	GoalBlocked
+	// StartInvalid means that the start node was rejected by Options.StartValid.
+	StartInvalid
+)
+
+func (r UnreachableReason) String() string {
+	switch r {
+	case StartIsolated:
+		return "start isolated"
+	case GoalNeverExpanded:
+		return "goal never expanded"
+	case GoalBlocked:
+		return "goal blocked"
+	case StartInvalid:
+		return "start invalid"
+	default:
+		return "unknown"
+	}
+}
+
+// Diagnose runs Dijkstra's algorithm and checks whether the goal is reachable.
+// If it is not, the returned NotReachableError has its Reason inferred from the search.
+// The search is instrumented for the diagnosis, so prefer Dijkstra when the reason is not needed.
+// An error stopping the search is returned as is, such as LimitReachedError when MaxNodes is reached
+// or NotReachableError with StartInvalid when the start node is rejected by StartValid.
+func (c Options[K, C]) Diagnose(start, goal K, initial C) (costs map[K]Node[K, C], err error) {
+	c = c.prepare()
+	start, goal = c.canonical(start), c.canonical(goal)
+	// The flags are atomic as the edges may be accumulated concurrently with ParallelEdges.
+	var startPassable, goalGenerated atomic.Bool
+	// generated records whether an edge leads to the goal.
+	generated := func(to K) {
+		if c.canonical(to) == goal {
+			goalGenerated.Store(true)
+		}
+	}
+	// passable records whether an edge leaving the start is passable.
+	passable := func(from K, ok bool) {
+		if ok && from == start {
+			startPassable.Store(true)
+		}
+	}
+	switch {
+	case c.WeightedEdges != nil:
+		weightedEdges := c.WeightedEdges
+		c.WeightedEdges = func(from K) []Edge[K, C] {
+			dest := weightedEdges(from)
+			for _, edge := range dest {
+				generated(edge.To)
+				passable(from, true)
+			}
+			return dest
+		}
+	case c.edgesAt != nil:
+		edgesAt := c.edgesAt
+		c.edgesAt = func(from K, agg C) []K {
+			dest := edgesAt(from, agg)
+			for _, to := range dest {
+				generated(to)
+			}
+			return dest
+		}
+	case c.EdgesCtx != nil:
+		edgesCtx := c.EdgesCtx
+		c.EdgesCtx = func(ctx context.Context, from K) ([]K, error) {
+			dest, err := edgesCtx(ctx, from)
+			for _, to := range dest {
+				generated(to)
+			}
+			return dest, err
+		}
+	default:
+		edges := c.Edges
+		c.Edges = func(from K) []K {
+			dest := edges(from)
+			for _, to := range dest {
+				generated(to)
+			}
+			return dest
+		}
+	}
+	if c.AccumulatorCtx != nil {
+		accumulatorCtx := c.AccumulatorCtx
+		c.AccumulatorCtx = func(ctx context.Context, agg C, from, to K) (C, bool, error) {
+			next, ok, err := accumulatorCtx(ctx, agg, from, to)
+			passable(from, ok && err == nil)
+			return next, ok, err
+		}
+	} else if c.Accumulator != nil {
+		accumulator := c.Accumulator
+		c.Accumulator = func(agg C, from, to K) (C, bool) {
+			next, ok := accumulator(agg, from, to)
+			passable(from, ok)
+			return next, ok
+		}
+	}
+	costs, err = c.try(query[K, C]{starts: []K{start}, initial: initial})
+	if err != nil {
+		return costs, err
+	}
+	if _, ok := costs[goal]; ok {
+		return costs, nil
+	}
+	err = newNotReachableError(costs, c.Less, goal)
+	var notReachableErr *NotReachableError[K, C]
+	if errors.As(err, &notReachableErr) {
+		switch {
+		case !startPassable.Load():
+			notReachableErr.Reason = StartIsolated
+		case !goalGenerated.Load():
+			notReachableErr.Reason = GoalNeverExpanded
+		default:
+			notReachableErr.Reason = GoalBlocked
+		}
+	}
+	return costs, err
+}
+
+// OneWayEdge is an edge whose reverse is missing, found by VerifyUndirected.
+type OneWayEdge[K comparable] struct {
+	From K
+	To   K
+}
+
+// VerifyUndirected checks that edges describes an undirected graph, e.g. in tests of an Edges function.
+// returns : Every edge leaving one of the nodes whose reverse edge is missing, in the order of nodes and their edges.
+func VerifyUndirected[K comparable](nodes []K, edges func(K) []K) []OneWayEdge[K] {
+	adjacent := make(map[K]map[K]struct{})
+	neighbors := func(node K) map[K]struct{} {
+		if set, ok := adjacent[node]; ok {
+			return set
+		}
+		set := make(map[K]struct{})
+		for _, to := range edges(node) {
+			set[to] = struct{}{}
+		}
+		adjacent[node] = set
+		return set
+	}
+	var missing []OneWayEdge[K]
+	for _, from := range nodes {
+		for _, to := range edges(from) {
+			if _, ok := neighbors(to)[from]; !ok {
+				missing = append(missing, OneWayEdge[K]{From: from, To: to})
+			}
+		}
+	}
+	return missing
+}
@@ -0,0 +1,107 @@
+package dijkstra_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnose(t *testing.T) {
+	graph := Text2Graph(`
+	1  1  ■  1 
+	1  1  ■  1 
+	■  ■  ■  1 
+	`)
+	walled := MockOptions(graph)
+	isolated := MockOptions(graph)
+	isolated.Accumulator = func(agg Cost, from, to Key) (Cost, bool) {
+		return agg, false
+	}
+	blocked := MockOptions(graph)
+	blocked.Edges = func(p Key) []Key {
+		return []Key{{X: p.X, Y: p.Y + 1}, {X: p.X + 1, Y: p.Y}}
+	}
+	weighted := func(options dijkstra.Options[Key, Cost]) dijkstra.Options[Key, Cost] {
+		return dijkstra.Options[Key, Cost]{
+			Less: options.Less,
+			Add:  func(agg, weight Cost) Cost { return agg + weight },
+			WeightedEdges: func(from Key) []dijkstra.Edge[Key, Cost] {
+				return lo.Map(options.Edges(from), func(to Key, _ int) dijkstra.Edge[Key, Cost] {
+					return dijkstra.Edge[Key, Cost]{To: to, Weight: graph[to]}
+				})
+			},
+		}
+	}
+	blockedCtx := blocked
+	blockedCtx.Edges = nil
+	blockedCtx.EdgesCtx = func(ctx context.Context, p Key) ([]Key, error) {
+		return blocked.Edges(p), nil
+	}
+	lonely := MockOptions(graph)
+	lonely.Edges = func(p Key) []Key { return nil }
+	invalid := MockOptions(graph)
+	invalid.StartValid = func(start Key) bool { return false }
+	parallel := MockOptions(graph)
+	parallel.ParallelEdges = true
+	for _, tc := range []struct {
+		name    string
+		options dijkstra.Options[Key, Cost]
+		goal    Key
+		reason  dijkstra.UnreachableReason
+	}{
+		{name: "isolated", options: isolated, goal: Key{X: 0, Y: 3}, reason: dijkstra.StartIsolated},
+		{name: "never expanded", options: walled, goal: Key{X: 0, Y: 3}, reason: dijkstra.GoalNeverExpanded},
+		{name: "blocked", options: blocked, goal: Key{X: 0, Y: 2}, reason: dijkstra.GoalBlocked},
+		{name: "blocked with context", options: blockedCtx, goal: Key{X: 0, Y: 2}, reason: dijkstra.GoalBlocked},
+		{name: "weighted isolated", options: weighted(lonely), goal: Key{X: 0, Y: 3}, reason: dijkstra.StartIsolated},
+		{name: "weighted never expanded", options: weighted(walled), goal: Key{X: 0, Y: 3}, reason: dijkstra.GoalNeverExpanded},
+		{name: "start invalid", options: invalid, goal: Key{X: 0, Y: 1}, reason: dijkstra.StartInvalid},
+		{name: "parallel never expanded", options: parallel, goal: Key{X: 0, Y: 3}, reason: dijkstra.GoalNeverExpanded},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			_, err := tc.options.Diagnose(Key{X: 0, Y: 0}, tc.goal, Cost(0))
+			var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+			a.ErrorAs(err, &notReachableErr)
+			a.Equal(tc.reason, notReachableErr.Reason)
+			a.Contains(err.Error(), tc.reason.String())
+		})
+	}
+}
+
+func TestDiagnoseLimitReached(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(3, 3, 1))
+	options.MaxNodes = 2
+	costs, err := options.Diagnose(Key{X: 0, Y: 0}, Key{X: 2, Y: 2}, Cost(0))
+	var limitErr *dijkstra.LimitReachedError[Key, Cost]
+	a.ErrorAs(err, &limitErr)
+	a.Len(costs, 2)
+}
+
+func TestDiagnoseReachable(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(3, 3, 1))
+	costs, err := options.Diagnose(Key{X: 0, Y: 0}, Key{X: 2, Y: 2}, Cost(0))
+	a.NoError(err)
+	a.Equal(Cost(4), costs[Key{X: 2, Y: 2}].Cost)
+}
+
+func TestVerifyUndirected(t *testing.T) {
+	a := assert.New(t)
+	graph := FlatGraph(3, 3, 1)
+	options := MockOptions(graph)
+	a.Empty(dijkstra.VerifyUndirected(lo.Keys(graph), options.Edges))
+
+	adj := map[string][]string{
+		"a": {"b", "c"},
+		"b": {"a"},
+		"c": {"d"},
+		"d": {"c"},
+	}
+	edges := func(from string) []string { return adj[from] }
+	a.Equal([]dijkstra.OneWayEdge[string]{{From: "a", To: "c"}}, dijkstra.VerifyUndirected([]string{"a", "b", "c", "d"}, edges))
+}
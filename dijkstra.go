@@ -2,18 +2,57 @@ package dijkstra
 
 import (
 	"container/heap"
+	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"math"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
 )
 
+// Node is a node reached by the search with its cost and predecessor.
 type Node[K comparable, C any] struct {
 	Key  K
 	Cost C
 	Prev *K
+	// Data is the payload produced by Options.Payload when the node was reached.
+	Data any
+	// Prevs are all the predecessors reaching the node at its cost.
+	// It is only recorded by Options.DijkstraAllPaths.
+	Prevs []K
+	// Finalized is whether the cost is known to be the lowest, as the node was settled by the search,
+	// rather than a tentative cost, e.g. of costs assembled by hand or left by a failed search.
+	Finalized bool
+}
+
+// NodeCost is an alias of Node.
+type NodeCost[K comparable, C any] = Node[K, C]
+
+// heapNode is a node queued with the priority used to order it.
+// The priority equals the node's cost unless a search orders by an estimate.
+type heapNode[K comparable, C any] struct {
+	Node[K, C]
+	priority C
+	// origin is the index of the start node the node was reached from.
+	origin int
+	seq    uint64
+	// index is the position of the node in the heap.
+	index int
 }
 
 type heapNodes[K comparable, C any] struct {
-	nodes []*Node[K, C]
+	nodes []*heapNode[K, C]
 	less  func(i, j C) bool
+	// tiebreak orders nodes with equal priorities by key, if set.
+	tiebreak func(i, j K) bool
+	// fifo orders nodes with equal priorities by insertion.
+	fifo bool
+	seq  uint64
 }
 
 func (pq *heapNodes[K, C]) Len() int {
@@ -21,20 +60,43 @@ func (pq *heapNodes[K, C]) Len() int {
 }
 
 func (pq *heapNodes[K, C]) Less(i, j int) bool {
-	return pq.less(pq.nodes[i].Cost, pq.nodes[j].Cost)
+	a, b := pq.nodes[i], pq.nodes[j]
+	if pq.less(a.priority, b.priority) {
+		return true
+	}
+	if pq.less(b.priority, a.priority) {
+		return false
+	}
+	if a.origin != b.origin {
+		return a.origin < b.origin
+	}
+	if pq.tiebreak != nil {
+		if pq.tiebreak(a.Key, b.Key) {
+			return true
+		}
+		if pq.tiebreak(b.Key, a.Key) {
+			return false
+		}
+	}
+	return pq.fifo && a.seq < b.seq
 }
 
 func (pq *heapNodes[K, C]) Swap(i, j int) {
 	pq.nodes[i], pq.nodes[j] = pq.nodes[j], pq.nodes[i]
+	pq.nodes[i].index = i
+	pq.nodes[j].index = j
 }
 
 func (pq *heapNodes[K, C]) Push(x any) {
-	pq.nodes = append(pq.nodes, x.(*Node[K, C]))
+	node := x.(*heapNode[K, C])
+	node.index = len(pq.nodes)
+	pq.nodes = append(pq.nodes, node)
 }
 
 func (pq *heapNodes[K, C]) Pop() any {
 	last := pq.nodes[len(pq.nodes)-1]
 	pq.nodes = pq.nodes[:len(pq.nodes)-1]
+	last.index = -1
 	return last
 }
 
@@ -45,9 +107,9 @@ type priorityNodes[K comparable, C any] struct {
 	*heapNodes[K, C]
 }
 
-func newPriorityNodes[K comparable, C any](less func(i, j C) bool) *priorityNodes[K, C] {
+func newPriorityNodes[K comparable, C any](less func(i, j C) bool, size int) *priorityNodes[K, C] {
 	h := &heapNodes[K, C]{
-		nodes: []*Node[K, C]{},
+		nodes: make([]*heapNode[K, C], 0, size),
 		less:  less,
 	}
 	heap.Init(h)
@@ -55,13 +117,44 @@ func newPriorityNodes[K comparable, C any](less func(i, j C) bool) *priorityNode
 }
 
 // Extracts the minimum cost node from the priority queue
-func (pq *priorityNodes[K, C]) Pop() (current K, prev *K, cost C) {
-	nc := heap.Pop(pq.heapNodes).(*Node[K, C])
-	return nc.Key, nc.Prev, nc.Cost
+func (pq *priorityNodes[K, C]) Pop() Node[K, C] {
+	return pq.pop().Node
+}
+
+// Push queues the node ordered by priority, which is its cost unless a search orders by an estimate.
+func (pq *priorityNodes[K, C]) Push(node Node[K, C], priority C) {
+	pq.push(&heapNode[K, C]{Node: node, priority: priority})
+}
+
+func (pq *priorityNodes[K, C]) pop() *heapNode[K, C] {
+	return heap.Pop(pq.heapNodes).(*heapNode[K, C])
+}
+
+func (pq *priorityNodes[K, C]) push(entry *heapNode[K, C]) {
+	pq.seq++
+	entry.seq = pq.seq
+	heap.Push(pq.heapNodes, entry)
 }
 
-func (pq *priorityNodes[K, C]) Push(current K, prev *K, cost C) {
-	heap.Push(pq.heapNodes, &Node[K, C]{Key: current, Prev: prev, Cost: cost})
+// fix restores the order after the priority of a queued node has been lowered.
+func (pq *priorityNodes[K, C]) fix(entry *heapNode[K, C]) {
+	pq.seq++
+	entry.seq = pq.seq
+	heap.Fix(pq.heapNodes, entry.index)
+}
+
+func (pq *priorityNodes[K, C]) drain() []*heapNode[K, C] {
+	sort.Sort(pq.heapNodes)
+	entries := slices.Clone(pq.nodes)
+	clear(pq.nodes)
+	pq.nodes = pq.nodes[:0]
+	pq.seq = 0
+	return entries
+}
+
+// Peek returns the priority of the next node to be popped.
+func (pq *priorityNodes[K, C]) Peek() C {
+	return pq.heapNodes.nodes[0].priority
 }
 
 func (pq *priorityNodes[K, C]) Empty() bool {
@@ -81,23 +174,514 @@ func Dijkstra[K comparable, C any](
 	less func(i C, j C) bool,
 	edges func(from K) (dest []K),
 ) (costs map[K]Node[K, C]) {
-	open := newPriorityNodes[K](less)
-	costs = make(map[K]Node[K, C])
+	search := NewSearch[K](less)
+	search.Seed(start, initial)
+	costs = search.Costs()
+	for {
+		current, node, ok := search.Next()
+		if !ok {
+			return costs
+		}
+		for _, dest := range edges(current) {
+			if _, ok := costs[dest]; ok {
+				continue
+			}
+			if destCost, ok := accumulator(node.Cost, current, dest); ok {
+				search.Relax(current, dest, destCost)
+			}
+		}
+	}
+}
+
+// query describes a single run of the search.
+type query[K comparable, C any] struct {
+	// starts are seeded with the initial cost.
+	// Nodes reached at equal costs from several start nodes are attributed to the first of them.
+	starts  []K
+	initial C
+	// stop ends the search once it reports true for a settled node.
+	stop func(node Node[K, C]) bool
+	// estimate is added to the cost of a node to order the queue, as in A*.
+	estimate func(key K) C
+	// allPrevs records every predecessor reaching a node at its cost.
+	allPrevs bool
+	// ctx cancels the search once it is done.
+	ctx context.Context
+	// within rejects the nodes whose cost it reports false for.
+	within func(cost C) bool
+	// stats collects the work done by the search.
+	stats *Stats
+	// work holds the buffers to reuse, if any.
+	work *workspace[K, C]
+	// frontier receives the keys still queued when the search stops, in the order they would be settled.
+	frontier *[]K
+	// repair settles again the nodes reached at a lower cost than settled, to patch the costs in work.
+	repair bool
+	// trace receives the steps of the search, if not nil.
+	trace *[]Event[K, C]
+}
+
+// DijkstraWeighted runs Dijkstra's algorithm over edges carrying their weights.
+// initial : The initial cost to reach the start node.
+// less : Comparison function to determine the order of costs.
+// add : Function to add the weight of an edge to the accumulated cost.
+// edges : Function to retrieve the passable edges leaving a node.
+// returns : The costs to reach each node from the start node.
+func DijkstraWeighted[K comparable, C any](
+	start K,
+	initial C,
+	less func(i C, j C) bool,
+	add func(agg C, weight C) C,
+	edges func(from K) []Edge[K, C],
+) (costs map[K]Node[K, C]) {
+	return Options[K, C]{
+		Less:          less,
+		Add:           add,
+		WeightedEdges: edges,
+	}.search(start, initial)
+}
 
-	open.Push(start, nil, initial)
+func (c Options[K, C]) search(start K, initial C) (costs map[K]Node[K, C]) {
+	return c.run(query[K, C]{starts: []K{start}, initial: initial})
+}
+
+// priority computes the priority of a node queued at the given cost.
+func (q query[K, C]) priority(add func(a, b C) C, key K, cost C) C {
+	if q.estimate == nil {
+		return cost
+	}
+	return add(cost, q.estimate(key))
+}
+
+// run runs a query without a context, panicking if the search fails as requested by the options,
+// such as with StrictMonotonic.
+func (c Options[K, C]) run(q query[K, C]) (costs map[K]Node[K, C]) {
+	return must(c.try(q))
+}
+
+// progressInterval is the number of settled nodes between the calls of Options.OnProgress.
+const progressInterval = 64
+
+// must returns the costs, panicking with the error unless it only reports that MaxNodes was reached
+// or that the start nodes were rejected by StartValid.
+func must[K comparable, C any](costs map[K]Node[K, C], err error) map[K]Node[K, C] {
+	switch err := err.(type) {
+	case nil, *LimitReachedError[K, C]:
+		return costs
+	case *NotReachableError[K, C]:
+		if err.Reason == StartInvalid {
+			return costs
+		}
+	}
+	panic(err)
+}
+
+// try runs the query, returning the costs settled so far together with the error that stopped it.
+func (c Options[K, C]) try(q query[K, C]) (costs map[K]Node[K, C], err error) {
+	// A repair continues the last run, and UpdateEdge clears the caches before it.
+	if !q.repair {
+		c.begin()
+	}
+	var open openSet[K, C]
+	// queued holds the entry of every node in the queue, so that a cheaper cost found
+	// for a queued node updates its entry instead of queuing a duplicate.
+	var queued map[K]*heapNode[K, C]
+	if q.work != nil {
+		open, queued, costs = q.work.open, q.work.queued, q.work.costs
+	} else {
+		queued = make(map[K]*heapNode[K, C])
+		open = c.newOpenSet(queued, c.SizeHint)
+		costs = make(map[K]Node[K, C], c.SizeHint)
+	}
+	ctx := q.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	skipSettled := !q.allPrevs && !q.repair && c.OnRelax == nil && !c.StrictMonotonic && c.PreferLowerPrev == nil && !c.ReopenClosed && c.Resettle == nil
+
+	invalid := 0
+	for i, start := range q.starts {
+		start = c.canonical(start)
+		if _, ok := queued[start]; ok {
+			continue
+		}
+		if c.Blocked != nil && c.Blocked(start) {
+			continue
+		}
+		if c.StartValid != nil && !c.StartValid(start) {
+			invalid++
+			continue
+		}
+		entry := q.work.entry()
+		entry.Node, entry.priority, entry.origin = Node[K, C]{Key: start, Cost: q.initial}, q.priority(c.Add, start, q.initial), i
+		open.push(entry)
+		queued[start] = entry
+		q.stats.pushed(open.Len())
+		q.record(Event[K, C]{Kind: EventSeed, Key: start, Cost: q.initial})
+	}
+	if len(q.starts) > 0 && invalid == len(q.starts) {
+		start := c.canonical(q.starts[0])
+		return costs, &NotReachableError[K, C]{Costs: costs, Start: start, Goal: start, StartingUnknown: true, Reason: StartInvalid}
+	}
+	finalized := 0
+	// progress calls OnProgress, unless it was already called with the same count.
+	var progress func()
+	if c.OnProgress != nil && (c.MaxNodes > 0 || q.within != nil) {
+		reported := -1
+		progress = func() {
+			if finalized == reported {
+				return
+			}
+			reported = finalized
+			bound := c.MaxNodes
+			if bound <= 0 {
+				bound = finalized + open.Len()
+			}
+			c.OnProgress(finalized, bound)
+		}
+		defer progress()
+	}
 	for !open.Empty() {
-		current, prev, cost := open.Pop()
-		if _, ok := costs[current]; ok {
+		if q.ctx != nil {
+			if err := q.ctx.Err(); err != nil {
+				return costs, err
+			}
+		}
+		if c.MaxNodes > 0 && finalized >= c.MaxNodes {
+			return costs, &LimitReachedError[K, C]{Costs: costs, Limit: c.MaxNodes}
+		}
+		entry := open.pop()
+		if entry == nil {
+			q.stats.stale()
 			continue
 		}
-		costs[current] = Node[K, C]{Key: current, Cost: cost, Prev: prev}
-		for _, dest := range edges(current) {
-			if destCost, ok := accumulator(cost, current, dest); ok {
-				open.Push(dest, &current, destCost)
+		finalized++
+		delete(queued, entry.Key)
+		node := entry.Node
+		node.Finalized = true
+		q.record(Event[K, C]{Kind: EventPop, Key: node.Key, Cost: node.Cost})
+		costs[node.Key] = node
+		q.record(Event[K, C]{Kind: EventFinalize, Key: node.Key, Cost: node.Cost})
+		if q.stats != nil {
+			q.stats.Finalized++
+		}
+		if c.OnFinalize != nil {
+			c.OnFinalize(node.Key, node)
+		}
+		if progress != nil && finalized%progressInterval == 0 {
+			progress()
+		}
+		if q.stop != nil && q.stop(node) {
+			break
+		}
+		current := node.Key
+		var failure error
+		// improve applies the candidate cost of dest, and reports whether it was accepted.
+		improve := func(dest K, destCost C) bool {
+			if failure != nil {
+				return false
+			}
+			if !c.valid(destCost) {
+				failure = &InvalidCostError[K, C]{From: current, To: dest, Cost: destCost}
+				return false
+			}
+			if c.StrictMonotonic && c.Less(destCost, node.Cost) {
+				failure = &NonMonotonicCostError[K, C]{From: current, To: dest, Agg: node.Cost, Next: destCost}
+				return false
+			}
+			if q.within != nil && !q.within(destCost) {
+				return false
+			}
+			next := Node[K, C]{Key: dest, Cost: destCost, Prev: &current}
+			if c.Payload != nil {
+				next.Data = c.Payload(node.Prev, current, dest, destCost)
+			}
+			if q.allPrevs {
+				next.Prevs = []K{current}
+			}
+			if settled, ok := costs[dest]; ok {
+				if !c.settleTie(q, costs, settled, next) {
+					q.stats.stale()
+					return false
+				}
+			}
+			if e, ok := queued[dest]; ok {
+				prevs := e.Prevs
+				if q.allPrevs {
+					prevs = c.tie(e.Node, next).Prevs
+				}
+				same := c.equal(destCost, e.Cost)
+				switch {
+				case c.Less(destCost, e.Cost) || (same && entry.origin < e.origin):
+					if same {
+						next.Prevs = prevs
+					}
+					e.Node, e.priority, e.origin = next, q.priority(c.Add, dest, destCost), entry.origin
+					open.fix(e)
+					if q.stats != nil {
+						q.stats.Updates++
+					}
+					return true
+				case c.preferPrev(e.Node, next):
+					next.Prevs = prevs
+					e.Node = next
+					q.stats.stale()
+					return true
+				default:
+					e.Prevs = prevs
+					q.stats.stale()
+					return false
+				}
+			}
+			e := q.work.entry()
+			e.Node, e.priority, e.origin = next, q.priority(c.Add, dest, destCost), entry.origin
+			open.push(e)
+			queued[dest] = e
+			q.stats.pushed(open.Len())
+			return true
+		}
+		relax := func(dest K, destCost C) {
+			accepted := improve(dest, destCost)
+			if failure != nil {
+				return
+			}
+			q.record(Event[K, C]{Kind: EventRelax, Key: dest, From: &current, Cost: destCost, Accepted: accepted})
+			if c.OnRelax != nil {
+				c.OnRelax(current, dest, destCost, accepted)
+			}
+		}
+		// closed skips the self-loops, which never lower the cost of a settled node,
+		// the edges into blocked nodes, and into settled nodes before computing their costs
+		// when nothing would be done with those costs.
+		closed := func(dest K) bool {
+			if dest == current {
+				return true
+			}
+			if c.Blocked != nil && c.Blocked(dest) {
+				return true
+			}
+			if !skipSettled {
+				return false
+			}
+			if _, ok := costs[dest]; !ok {
+				return false
+			}
+			q.stats.stale()
+			q.record(Event[K, C]{Kind: EventSkipStale, Key: dest, From: &current})
+			return true
+		}
+		if c.WeightedEdges != nil {
+			for _, edge := range c.WeightedEdges(current) {
+				if to := c.canonical(edge.To); !closed(to) {
+					relax(to, c.penalize(to, c.Add(node.Cost, edge.Weight)))
+				}
+			}
+		} else {
+			dests, err := c.edges(ctx, current, node.Cost)
+			if err != nil {
+				return costs, err
+			}
+			if c.ParallelEdges && len(dests) > 1 {
+				dests = slices.DeleteFunc(slices.Clone(dests), closed)
+				if err := c.relaxParallel(ctx, node, dests, relax); err != nil {
+					return costs, err
+				}
+			} else {
+				for _, dest := range dests {
+					if closed(dest) {
+						continue
+					}
+					destCost, ok, err := c.accumulate(ctx, node.Cost, current, dest)
+					if err != nil {
+						return costs, err
+					}
+					if ok {
+						relax(dest, destCost)
+					}
+				}
 			}
 		}
+		q.work.release(entry)
+		if failure != nil {
+			return costs, failure
+		}
 	}
-	return costs
+	if q.frontier != nil {
+		entries := open.drain()
+		*q.frontier = make([]K, len(entries))
+		for i, entry := range entries {
+			(*q.frontier)[i] = entry.Key
+			q.work.release(entry)
+		}
+	}
+	return costs, nil
+}
+
+// relaxParallel computes the costs of the edges from the node to dests concurrently,
+// then relaxes them in order.
+func (c Options[K, C]) relaxParallel(ctx context.Context, node Node[K, C], dests []K, relax func(dest K, destCost C)) error {
+	type result struct {
+		cost C
+		ok   bool
+		err  error
+	}
+	results := make([]result, len(dests))
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(dests)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int(next.Add(1) - 1); i < len(dests); i = int(next.Add(1) - 1) {
+				r := &results[i]
+				r.cost, r.ok, r.err = c.accumulate(ctx, node.Cost, node.Key, dests[i])
+			}
+		}()
+	}
+	wg.Wait()
+	for i, r := range results {
+		if r.err != nil {
+			return r.err
+		}
+		if r.ok {
+			relax(dests[i], r.cost)
+		}
+	}
+	return nil
+}
+
+// valid reports whether the cost passes RejectNaN and IsValid.
+func (c Options[K, C]) valid(cost C) bool {
+	if c.RejectNaN {
+		if v := reflect.ValueOf(cost); (v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64) && math.IsNaN(v.Float()) {
+			return false
+		}
+	}
+	return c.IsValid == nil || c.IsValid(cost)
+}
+
+// settleTie applies a candidate reaching an already settled node,
+// and reports whether the node should be queued to be settled again.
+func (c Options[K, C]) settleTie(q query[K, C], costs map[K]Node[K, C], settled, next Node[K, C]) bool {
+	if q.allPrevs {
+		settled = c.tie(settled, next)
+		costs[settled.Key] = settled
+	}
+	if c.preferPrev(settled, next) {
+		next.Prevs, next.Finalized = settled.Prevs, true
+		costs[settled.Key] = next
+	}
+	return c.reopen(settled, next) || (q.repair && c.Less(next.Cost, settled.Cost))
+}
+
+// accumulate computes the cost to reach to from from, including the penalty of visiting to.
+// It uses AccumulatorCtx when it is set.
+func (c Options[K, C]) accumulate(ctx context.Context, agg C, from, to K) (next C, ok bool, err error) {
+	if c.AccumulatorCtx != nil {
+		next, ok, err = c.AccumulatorCtx(ctx, agg, from, to)
+		if err != nil {
+			return next, false, fmt.Errorf("accumulating the cost from %v to %v: %w", from, to, err)
+		}
+	} else {
+		next, ok = c.Accumulator(agg, from, to)
+	}
+	if !ok {
+		return next, false, nil
+	}
+	return c.penalize(to, next), true, nil
+}
+
+// edges retrieves the edges leaving the node reached at agg, using edgesAt or EdgesCtx when it is set,
+// and canonicalizes them into a new slice when Canonical is set.
+func (c Options[K, C]) edges(ctx context.Context, from K, agg C) ([]K, error) {
+	var edges []K
+	if c.edgesAt != nil {
+		edges = c.edgesAt(from, agg)
+	} else if c.EdgesCtx == nil {
+		edges = c.Edges(from)
+	} else {
+		var err error
+		edges, err = c.EdgesCtx(ctx, from)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving the edges of %v: %w", from, err)
+		}
+	}
+	return c.canonicalKeys(edges), nil
+}
+
+// canonicalKeys returns the canonical forms of the keys, in a new slice if Canonical is set.
+func (c Options[K, C]) canonicalKeys(keys []K) []K {
+	if c.Canonical == nil {
+		return keys
+	}
+	canonical := make([]K, len(keys))
+	for i, key := range keys {
+		canonical[i] = c.Canonical(key)
+	}
+	return canonical
+}
+
+// neighbors calls visit with each node the edges leaving from lead to and its cost reached at agg,
+// resolving WeightedEdges, EdgesCtx and AccumulatorCtx like the search does.
+// It returns the first error of EdgesCtx or AccumulatorCtx.
+func (c Options[K, C]) neighbors(ctx context.Context, from K, agg C, visit func(to K, cost C)) error {
+	if c.WeightedEdges != nil {
+		for _, edge := range c.WeightedEdges(from) {
+			to := c.canonical(edge.To)
+			visit(to, c.penalize(to, c.Add(agg, edge.Weight)))
+		}
+		return nil
+	}
+	dests, err := c.edges(ctx, from, agg)
+	if err != nil {
+		return err
+	}
+	for _, to := range dests {
+		cost, ok, err := c.accumulate(ctx, agg, from, to)
+		if err != nil {
+			return err
+		}
+		if ok {
+			visit(to, cost)
+		}
+	}
+	return nil
+}
+
+// canonical returns the canonical form of the key with Canonical, if it is set.
+func (c Options[K, C]) canonical(key K) K {
+	if c.Canonical == nil {
+		return key
+	}
+	return c.Canonical(key)
+}
+
+// penalize adds the penalty of visiting the node to its cost.
+func (c Options[K, C]) penalize(node K, cost C) C {
+	if c.NodePenalty == nil {
+		return cost
+	}
+	return c.Add(cost, c.NodePenalty(node))
+}
+
+// preferPrev reports whether the popped node reaches the settled node at the same cost
+// from a predecessor preferred by PreferLowerPrev.
+func (c Options[K, C]) preferPrev(settled, popped Node[K, C]) bool {
+	if c.PreferLowerPrev == nil || settled.Prev == nil || popped.Prev == nil {
+		return false
+	}
+	return c.PreferLowerPrev(*popped.Prev, *settled.Prev) && c.equal(popped.Cost, settled.Cost)
+}
+
+// reopen reports whether a settled node may be settled again with the cost of the popped node.
+func (c Options[K, C]) reopen(settled, popped Node[K, C]) bool {
+	if !c.ReopenClosed && c.Resettle == nil {
+		return false
+	}
+	if !c.Less(popped.Cost, settled.Cost) {
+		return false
+	}
+	return c.ReopenClosed || c.Resettle(settled.Key, settled.Cost, popped.Cost)
 }
 
 // Options defines the options for running Dijkstra's algorithm.
@@ -110,59 +694,672 @@ type Options[K comparable, C any] struct {
 	Less func(i C, j C) bool
 	// Function to retrieve adjacent nodes.
 	Edges func(from K) (dest []K)
+	// Optional function to produce a payload stored in Node.Data.
+	// prev is the predecessor of from, and cost is the accumulated cost to reach to.
+	// The payload is recomputed for every candidate, so the stored value is
+	// overwritten whenever a cheaper way to reach the node is found.
+	Payload func(prev *K, from, to K, cost C) any
+	// Optional function to retrieve the nodes that have an edge into the given node.
+	// It is required by searches running backwards from the goal.
+	// The accumulator is still called as (agg, from, to) in the direction of the original edge.
+	ReverseEdges func(to K) (from []K)
+	// Whether a settled node may be settled and expanded again when a strictly cheaper cost is found later.
+	// This is needed when nodes are ordered by an inconsistent heuristic, at the cost of re-expansions.
+	// When false, nodes are settled once, so any heuristic must be consistent.
+	ReopenClosed bool
+	// Optional function to decide whether a settled node is settled and expanded again
+	// when a cheaper cost is found for it later.
+	// This is an escape hatch for graphs that change during the search or have negative edges;
+	// re-expansions void the usual complexity guarantee of Dijkstra's algorithm.
+	// When nil, nodes are settled once unless ReopenClosed is set.
+	Resettle func(node K, oldCost, newCost C) bool
+	// Function to add two costs. Required by the options that combine costs, such as NodePenalty.
+	Add func(a, b C) C
+	// Optional function to retrieve the penalty for visiting a node, which is added with Add
+	// to the accumulated cost of every path entering the node. The start node is not penalized.
+	// Penalties must not be negative, otherwise the costs are not guaranteed to be the lowest.
+	NodePenalty func(node K) C
+	// Optional function to determine whether two costs are equal, such as within a tolerance for floats.
+	// Defaults to !Less(a, b) && !Less(b, a).
+	Equal func(a, b C) bool
+	// Optional comparison function to choose the predecessor of a node reached at equal costs
+	// from several nodes, keeping the one for which PreferLowerPrev(a, b) reports a before b.
+	// This makes the resolved paths canonical regardless of the queue order.
+	PreferLowerPrev func(a, b K) bool
+	// Whether nodes queued at equal costs are expanded in insertion order,
+	// which grows breadth-first trees on uniform-cost graphs.
+	// It only changes how ties are resolved, never the costs.
+	FIFOTies bool
+	// Optional function to estimate the cost from a node to the goal, used by AStar.
+	// The estimate is added to the accumulated cost with Add to order the queue.
+	// It must never overestimate for the path to be the shortest, and must be consistent
+	// unless ReopenClosed is set.
+	Heuristic func(from, goal K) C
+	// Whether AStar checks that Heuristic never overestimates the cost to the goal of the nodes it queued,
+	// failing with InadmissibleHeuristicError for the first one that it does.
+	// The actual costs are computed with DijkstraReverse after the search, so it is meant for development only,
+	// and it requires ReverseEdges, even for a symmetric graph, failing with ErrNoReverseEdges without it.
+	// TryAStar returns the error, while AStar panics with it.
+	DebugAdmissible bool
+	// Optional function to retrieve the edges leaving a node together with their weights.
+	// When set, it is used instead of Edges and Accumulator, and the cost of an edge is
+	// the accumulated cost plus its weight with Add. Impassable edges are left out.
+	WeightedEdges func(from K) []Edge[K, C]
+	// Whether to fail the search with NonMonotonicCostError when an edge lowers the accumulated cost,
+	// which would make the costs silently wrong.
+	// TryDijkstra and DijkstraContext return the error, while the other searches panic with it.
+	StrictMonotonic bool
+	// Optional function to retrieve the edges leaving a node, used instead of Edges,
+	// e.g. for edges looked up in a database. It receives the context of DijkstraContext.
+	// An error aborts the search: TryDijkstra and DijkstraContext return it wrapped, while the other searches panic with it.
+	// It is not used by the searches over reverse edges, which need ReverseEdges or Edges.
+	EdgesCtx func(ctx context.Context, from K) ([]K, error)
+	// Optional function to accumulate costs, used instead of Accumulator.
+	// It receives the context and fails the search like EdgesCtx.
+	AccumulatorCtx func(ctx context.Context, agg C, from, to K) (C, bool, error)
+	// Whether to compute the costs of the edges leaving each settled node concurrently on up to GOMAXPROCS goroutines,
+	// for costly accumulators.
+	// The queue is still updated by a single goroutine, in the order of Edges, so the results are unchanged.
+	// Accumulator, AccumulatorCtx and NodePenalty are then called concurrently, so they must be safe for concurrent use.
+	// It is not used with WeightedEdges.
+	ParallelEdges bool
+	// Optional function to report whether a start node is part of the graph, as the start nodes are settled
+	// without calling Accumulator. When every start node is rejected, no node is settled,
+	// and TryDijkstra and DijkstraContext return NotReachableError with the StartInvalid reason.
+	StartValid func(start K) bool
+	// Optional function to report whether a node is blocked, e.g. a closed road,
+	// so that it is never settled even when it is returned by Edges or is a start node.
+	Blocked func(node K) bool
+	// Optional function called with each node settled by the search, in the order they are settled.
+	OnFinalize func(key K, node Node[K, C])
+	// Optional function called with each cost computed for an edge,
+	// and whether the cost was kept to settle to with, e.g. to animate the search.
+	OnRelax func(from, to K, newCost C, accepted bool)
+	// Optional maximum number of nodes to settle, or 0 for no limit, bounding the work on huge or unbounded graphs.
+	// TryDijkstra and DijkstraContext stop with LimitReachedError once it is reached,
+	// while the other searches return the costs settled so far.
+	MaxNodes int
+	// Whether to fail the search with InvalidCostError when an edge produces a NaN cost,
+	// for floating-point costs, which would break the order of the queue.
+	// Impassable edges should be left out or rejected by Accumulator rather than cost +Inf,
+	// as +Inf costs are valid and their nodes are settled last.
+	// TryDijkstra and DijkstraContext return the error, while the other searches panic with it.
+	RejectNaN bool
+	// Optional function to report whether a cost is valid, failing the search like RejectNaN otherwise.
+	IsValid func(cost C) bool
+	// Optional function to order the nodes queued at equal costs by key, before FIFOTies.
+	// It makes the paths independent of the order of Edges, and also breaks the ties of Farthest.
+	// It only changes how ties are resolved, never the costs.
+	Tiebreak func(i, j K) bool
+	// Optional function to create the priority queue of the searches running Dijkstra's algorithm,
+	// such as NewBucketQueue, in place of the default binary heap.
+	// The queue orders the nodes queued at equal priorities itself, so Tiebreak, FIFOTies
+	// and the order of multiple start nodes no longer apply.
+	NewQueue func(less func(i, j C) bool) PriorityQueue[K, C]
+	// Whether Accumulator rejects edges depending on the accumulated cost, e.g. beyond a fuel budget,
+	// so that Reachable runs the search with the costs instead of a plain traversal.
+	CostGated bool
+	// Optional function called every few settled nodes and once the search stops, to report the progress
+	// of the searches bounded by MaxNodes or by the cost of DijkstraWithin, e.g. to render a progress bar.
+	// bound is MaxNodes, or without it the number of nodes settled or queued so far,
+	// an estimate growing as the search discovers nodes within the cost.
+	// It is never called by unbounded searches.
+	OnProgress func(finalized int, bound int)
+	// Optional function to map a key to the key identifying the same node,
+	// e.g. clearing the fields of a struct key that do not affect its identity, so that equal nodes are deduplicated.
+	// It is applied to the start nodes and to the nodes returned by Edges, EdgesCtx and WeightedEdges,
+	// so the other functions and the costs only see canonical keys,
+	// and to every other key passed in, such as the goals of DijkstraTo, ShortestPath and PathCost,
+	// so the maps returned by PathsTo, AllPairs and SubsetDistances are keyed by canonical keys too.
+	// It must be idempotent: Canonical(Canonical(k)) == Canonical(k).
+	Canonical func(key K) K
+	// Optional expected number of reachable nodes, used to pre-allocate the queue and the costs.
+	// It only changes how memory is allocated, never the costs.
+	SizeHint int
+	// resets clears the caches scoped to one search, such as the one of WithMemoAccumulator.
+	resets []func()
+	// edgesAt retrieves the edges leaving a node reached at agg, used instead of Edges and EdgesCtx
+	// if not nil, such as by WithState.
+	edgesAt func(from K, agg C) []K
+}
+
+// begin clears the caches scoped to one search as a search starts.
+func (c Options[K, C]) begin() {
+	for _, reset := range c.resets {
+		reset()
+	}
+}
+
+func (c Options[K, C]) equal(a, b C) bool {
+	if c.Equal != nil {
+		return c.Equal(a, b)
+	}
+	return !c.Less(a, b) && !c.Less(b, a)
 }
 
 // Dijkstra runs Dijkstra's algorithm with the given options.
 func (c Options[K, C]) Dijkstra(start K, initial C) (costs map[K]Node[K, C]) {
-	if c.Edges == nil {
+	return c.prepare().search(start, initial)
+}
+
+// DijkstraContext runs Dijkstra's algorithm until it is done or the context is cancelled.
+// When cancelled, it returns the costs settled so far together with ctx.Err().
+// Those costs are final, so paths to the settled nodes can still be resolved with ShortestPath.
+func (c Options[K, C]) DijkstraContext(ctx context.Context, start K, initial C) (costs map[K]Node[K, C], err error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c.withDefaults().try(query[K, C]{starts: []K{start}, initial: initial, ctx: ctx})
+}
+
+// DijkstraWithin runs Dijkstra's algorithm, only reaching the nodes whose cost does not exceed max.
+// Nodes costing exactly max are included.
+func (c Options[K, C]) DijkstraWithin(start K, initial C, max C) (costs map[K]Node[K, C]) {
+	return c.prepare().run(query[K, C]{starts: []K{start}, initial: initial, within: func(cost C) bool {
+		return !c.Less(max, cost)
+	}})
+}
+
+// DijkstraSeq runs Dijkstra's algorithm lazily, yielding each node as soon as it is settled,
+// in non-decreasing order of cost. Breaking out of the loop stops the search.
+func (c Options[K, C]) DijkstraSeq(start K, initial C) iter.Seq2[K, Node[K, C]] {
+	c = c.prepare()
+	return func(yield func(K, Node[K, C]) bool) {
+		c.run(query[K, C]{starts: []K{start}, initial: initial, stop: func(node Node[K, C]) bool {
+			return !yield(node.Key, node)
+		}})
+	}
+}
+
+// Stats reports how much work a search did.
+type Stats struct {
+	// Finalized is the number of nodes settled, including nodes settled again.
+	Finalized int
+	// Pushes is the number of nodes added to the queue.
+	Pushes int
+	// Updates is the number of queued nodes whose cost was lowered in place.
+	Updates int
+	// Stale is the number of candidates discarded for not improving on the cost already known.
+	// Without updates in place, each of them would have been a stale entry in the queue.
+	Stale int
+	// MaxQueueLen is the largest number of nodes queued at once.
+	MaxQueueLen int
+}
+
+func (s *Stats) pushed(queueLen int) {
+	if s == nil {
+		return
+	}
+	s.Pushes++
+	s.MaxQueueLen = max(s.MaxQueueLen, queueLen)
+}
+
+func (s *Stats) stale() {
+	if s != nil {
+		s.Stale++
+	}
+}
+
+// DijkstraStats runs Dijkstra's algorithm like Dijkstra, also reporting how much work it did.
+func (c Options[K, C]) DijkstraStats(start K, initial C) (costs map[K]Node[K, C], stats Stats) {
+	costs = c.prepare().run(query[K, C]{starts: []K{start}, initial: initial, stats: &stats})
+	return costs, stats
+}
+
+// DijkstraMulti runs Dijkstra's algorithm from several start nodes at once,
+// all seeded with the initial cost.
+// Each node is reached from its cheapest start node, so ShortestPath walks back to that start node.
+// Ties between start nodes at equal costs are resolved in favor of the one listed first.
+func (c Options[K, C]) DijkstraMulti(starts []K, initial C) (costs map[K]Node[K, C]) {
+	return c.prepare().run(query[K, C]{starts: starts, initial: initial})
+}
+
+// DijkstraTo runs Dijkstra's algorithm until the goal node is settled.
+// The returned costs are partial: they only hold the nodes settled before the goal,
+// which is enough to resolve the path to the goal with ShortestPath.
+// If the goal is not reachable, the search settles every reachable node.
+func (c Options[K, C]) DijkstraTo(start, goal K, initial C) (costs map[K]Node[K, C]) {
+	goal = c.canonical(goal)
+	costs, _ = c.DijkstraUntil(start, initial, func(key K, _ Node[K, C]) bool {
+		return key == goal
+	})
+	return costs
+}
+
+// DijkstraToFrontier runs Dijkstra's algorithm like DijkstraTo, also returning the frontier:
+// the nodes reached but not settled when the goal was, in the order they would have been settled.
+// The frontier is empty if the goal is not reachable, as every reachable node is then settled.
+func (c Options[K, C]) DijkstraToFrontier(start, goal K, initial C) (costs map[K]Node[K, C], frontier []K) {
+	goal = c.canonical(goal)
+	costs = c.prepare().run(query[K, C]{starts: []K{start}, initial: initial, frontier: &frontier, stop: func(node Node[K, C]) bool {
+		return node.Key == goal
+	}})
+	return costs, frontier
+}
+
+// DijkstraUntil runs Dijkstra's algorithm until stop reports true for a settled node.
+// returns : The costs settled so far, and the node stop matched, or nil if none did.
+func (c Options[K, C]) DijkstraUntil(start K, initial C, stop func(K, Node[K, C]) bool) (costs map[K]Node[K, C], found *K) {
+	costs = c.prepare().run(query[K, C]{starts: []K{start}, initial: initial, stop: func(node Node[K, C]) bool {
+		if !stop(node.Key, node) {
+			return false
+		}
+		found = &node.Key
+		return true
+	}})
+	return costs, found
+}
+
+// withDefaults fills in the options that can be derived from the key type.
+func (c Options[K, C]) withDefaults() Options[K, C] {
+	if c.Edges == nil && c.Accumulator == nil && c.WeightedEdges == nil && c.EdgesCtx == nil && c.edgesAt == nil {
+		var k K
+		if _, ok := any(k).(interface{ AdjacentWeighted() []Edge[K, C] }); ok {
+			c.WeightedEdges = func(key K) []Edge[K, C] {
+				if adjacent, ok := any(key).(interface{ AdjacentWeighted() []Edge[K, C] }); ok {
+					return adjacent.AdjacentWeighted()
+				}
+				return nil
+			}
+		}
+	}
+	if c.Edges == nil && c.edgesAt == nil {
 		var k K
 		if _, ok := any(k).(interface{ Adjacent() []K }); ok {
 			c.Edges = func(key K) []K {
-				return any(key).(interface{ Adjacent() []K }).Adjacent()
+				if adjacent, ok := any(key).(interface{ Adjacent() []K }); ok {
+					return adjacent.Adjacent()
+				}
+				return nil
 			}
 		}
 	}
-	return Dijkstra(
-		start,
-		c.Accumulator,
-		initial,
-		c.Less,
-		c.Edges,
-	)
+	return c
+}
+
+// prepare fills in the defaults and panics with a descriptive error if the options are not valid.
+func (c Options[K, C]) prepare() Options[K, C] {
+	c = c.withDefaults()
+	if err := c.Validate(); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Validate checks that every option required to run a search is set.
+// Edges may be left nil when the key type implements Adjacent() []K.
+// Edges and Accumulator may both be left nil when the key type implements AdjacentWeighted() []Edge[K, C],
+// which is then used as WeightedEdges and requires Add.
+// returns : MissingOptionError naming the first missing field.
+func (c Options[K, C]) Validate() error {
+	c = c.withDefaults()
+	if c.Less == nil {
+		return &MissingOptionError{Field: "Less"}
+	}
+	if c.WeightedEdges != nil {
+		if c.Add == nil {
+			return &MissingOptionError{Field: "Add", RequiredBy: "WeightedEdges"}
+		}
+	} else {
+		if c.Edges == nil && c.EdgesCtx == nil && c.edgesAt == nil {
+			return &MissingOptionError{Field: "Edges"}
+		}
+		if c.Accumulator == nil && c.AccumulatorCtx == nil {
+			return &MissingOptionError{Field: "Accumulator"}
+		}
+	}
+	if c.NodePenalty != nil && c.Add == nil {
+		return &MissingOptionError{Field: "Add", RequiredBy: "NodePenalty"}
+	}
+	if c.Heuristic != nil && c.Add == nil {
+		return &MissingOptionError{Field: "Add", RequiredBy: "Heuristic"}
+	}
+	return nil
+}
+
+// TryDijkstra runs Dijkstra's algorithm like Dijkstra,
+// but returns errors instead of panicking, such as from Validate or StrictMonotonic.
+func (c Options[K, C]) TryDijkstra(start K, initial C) (costs map[K]Node[K, C], err error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c.withDefaults().try(query[K, C]{starts: []K{start}, initial: initial})
+}
+
+// PathResolve resolves the path from the start node to the goal node like ShortestPath,
+// but refuses to route through nodes whose costs are not finalized.
+// returns : The errors of ShortestPath, or NotFinalizedError if a node of the path is not finalized.
+func (c Options[K, C]) PathResolve(costs map[K]NodeCost[K, C], goal K) ([]K, error) {
+	path, err := c.ShortestPath(costs, goal)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		if !costs[path[i]].Finalized {
+			return nil, &NotFinalizedError[K]{Goal: goal, Node: path[i]}
+		}
+	}
+	return path, nil
 }
 
 // ShortestPath resolves the path from the start node to the goal node.
+// To resolve many goals against the same costs, prefer PathsTo or the functions of CreatePathFinders,
+// which find the start node of their NotReachableErrors only once.
+// returns : NotReachableError if the goal is not in the costs,
+// BrokenPathError if the Prev chain leads to a node missing from the costs, or CyclicPathError if it loops.
 func (c Options[K, C]) ShortestPath(costs map[K]Node[K, C], goal K) ([]K, error) {
+	return c.shortestPath(costs, goal, nil)
+}
+
+// shortestPath is ShortestPath, finding the start node of a NotReachableError through starts if it is not nil.
+func (c Options[K, C]) shortestPath(costs map[K]Node[K, C], goal K, starts *startCache[K, C]) ([]K, error) {
+	goal = c.canonical(goal)
 	if _, ok := costs[goal]; !ok {
-		return nil, newNotReachableError(costs, c.Less, goal)
+		if starts == nil {
+			starts = newStartCache(costs, c.Less)
+		}
+		return nil, starts.notReachable(goal)
 	}
-	path := []K{goal}
-	for {
-		current := path[0]
+	// The chain is walked twice, to check it and count its nodes, then to fill the path allocated once.
+	length := 0
+	for current, last := goal, goal; ; {
 		node, ok := costs[current]
 		if !ok {
-			return nil, newNotReachableError(costs, c.Less, goal)
+			return nil, &BrokenPathError[K]{Goal: goal, Node: last, Missing: current}
 		}
+		length++
 		if node.Prev == nil {
-			return path, nil
+			break
 		}
-		prev := *node.Prev
-		path = append([]K{prev}, path...)
+		if length > len(costs) {
+			return nil, newCyclicPathError(costs, goal)
+		}
+		current, last = *node.Prev, current
 	}
+	path := make([]K, length)
+	current := goal
+	for i := length - 1; i >= 0; i-- {
+		path[i] = current
+		if prev := costs[current].Prev; prev != nil {
+			current = *prev
+		}
+	}
+	return path, nil
+}
+
+// PathsTo resolves the paths from the start node to each of the goals,
+// walking each Prev only once even when the paths share their beginnings.
+// The paths may share their backing arrays, so copy a path before modifying it.
+// returns : The paths of the reachable goals, and a NotReachableError listing the goals missing from the costs if any,
+// or the BrokenPathError or CyclicPathError of the first goal in the costs whose path cannot be resolved.
+func (c Options[K, C]) PathsTo(costs map[K]Node[K, C], goals []K) (map[K][]K, error) {
+	goals = c.canonicalKeys(goals)
+	paths := make(map[K][]K, len(goals))
+	cache := newPathCache(costs)
+	var unreachable []K
+	for _, goal := range goals {
+		path, ok := cache.resolve(goal)
+		if !ok {
+			if _, ok := costs[goal]; ok {
+				// ShortestPath reports why the path cannot be resolved.
+				_, err := c.shortestPath(costs, goal, nil)
+				return nil, err
+			}
+			unreachable = append(unreachable, goal)
+			continue
+		}
+		paths[goal] = path
+	}
+	if len(unreachable) > 0 {
+		return paths, newNotReachableErrorMulti(costs, c.Less, unreachable)
+	}
+	return paths, nil
+}
+
+// pathCache memoizes the paths resolved against the costs, so that paths sharing their beginnings
+// walk each Prev only once. It is not safe for concurrent use.
+type pathCache[K comparable, C any] struct {
+	costs map[K]Node[K, C]
+	// resolved holds the path to every node walked so far, sharing their backing arrays.
+	resolved map[K][]K
+}
+
+func newPathCache[K comparable, C any](costs map[K]Node[K, C]) *pathCache[K, C] {
+	return &pathCache[K, C]{costs: costs, resolved: make(map[K][]K)}
+}
+
+// resolve returns the path to the goal, or false if it cannot be resolved,
+// as a node of the path is missing from the costs or the path loops.
+func (p *pathCache[K, C]) resolve(goal K) ([]K, bool) {
+	if path, ok := p.resolved[goal]; ok {
+		return path, true
+	}
+	var prefix, tail []K
+	for current := goal; ; {
+		if path, ok := p.resolved[current]; ok {
+			prefix = path
+			break
+		}
+		node, ok := p.costs[current]
+		if !ok || len(tail) == len(p.costs) {
+			return nil, false
+		}
+		tail = append(tail, current)
+		if node.Prev == nil {
+			break
+		}
+		current = *node.Prev
+	}
+	path := make([]K, len(prefix), len(prefix)+len(tail))
+	copy(path, prefix)
+	for i := len(tail) - 1; i >= 0; i-- {
+		path = append(path, tail[i])
+		p.resolved[tail[i]] = path[:len(path):len(path)]
+	}
+	return p.resolved[goal], true
 }
 
 // CreatePathFinder creates a function to find the path from the start node to any other node.
+// The function memoizes the paths it resolves, so the paths to many goals reuse their shared beginnings.
 func (c Options[K, C]) CreatePathFinder(start K, initial C) (resolvePath func(goal K) ([]K, error)) {
-	costs := c.Dijkstra(start, initial)
-	return func(goal K) ([]K, error) {
-		path, err := c.ShortestPath(costs, goal)
+	_, resolvePath = c.Solve(start, initial)
+	return resolvePath
+}
+
+// CreatePathFinderWithCosts creates a function to find the path from the start node to any other node,
+// with the cost accumulated up to each of its nodes as PathWithCosts does.
+func (c Options[K, C]) CreatePathFinderWithCosts(start K, initial C) (resolvePath func(goal K) ([]Node[K, C], error)) {
+	_, resolvePath = c.CreatePathFinders(start, initial)
+	return resolvePath
+}
+
+// CreatePathFinders creates the functions of CreatePathFinder and CreatePathFinderWithCosts,
+// sharing the costs of a single search.
+func (c Options[K, C]) CreatePathFinders(start K, initial C) (resolvePath func(goal K) ([]K, error), resolveNodes func(goal K) ([]Node[K, C], error)) {
+	return c.pathFinders(c.Dijkstra(start, initial))
+}
+
+// Solve runs Dijkstra's algorithm and returns the costs together with a function
+// resolving paths against exactly those costs.
+func (c Options[K, C]) Solve(start K, initial C) (costs map[K]Node[K, C], resolvePath func(goal K) ([]K, error)) {
+	costs = c.Dijkstra(start, initial)
+	resolvePath, _ = c.pathFinders(costs)
+	return costs, resolvePath
+}
+
+// pathFinders creates the functions resolving paths against the costs.
+// They memoize the paths they resolve, so that the paths to later goals reuse their beginnings,
+// and are safe for concurrent use.
+func (c Options[K, C]) pathFinders(costs map[K]Node[K, C]) (resolvePath func(goal K) ([]K, error), resolveNodes func(goal K) ([]Node[K, C], error)) {
+	paths := newPathCache(costs)
+	var mu sync.Mutex
+	// The start node of a NotReachableError is found once, not on every failed lookup.
+	starts := newStartCache(costs, c.Less)
+	resolvePath = func(goal K) ([]K, error) {
+		goal = c.canonical(goal)
+		mu.Lock()
+		path, ok := paths.resolve(goal)
+		mu.Unlock()
+		if !ok {
+			// ShortestPath reports why the path cannot be resolved.
+			return c.shortestPath(costs, goal, starts)
+		}
+		// The cached paths share their backing arrays, so each call gets its own copy.
+		return slices.Clone(path), nil
+	}
+	resolveNodes = func(goal K) ([]Node[K, C], error) {
+		path, err := resolvePath(goal)
 		if err != nil {
 			return nil, err
 		}
-		return path, nil
+		return nodesOf(costs, path), nil
+	}
+	return resolvePath, resolveNodes
+}
+
+// ErrNoEdges indicates that Options.Edges is not set and the key type does not implement Adjacent() []K.
+var ErrNoEdges = errors.New("Edges is not set and the key type does not implement Adjacent()")
+
+var _ error = &MissingOptionError{}
+
+// MissingOptionError indicates that a field of Options required to run a search is not set.
+type MissingOptionError struct {
+	Field string
+	// RequiredBy is the field that requires Field, if it is not always required.
+	RequiredBy string
+}
+
+func (e *MissingOptionError) Error() string {
+	if e.Field == "Edges" {
+		return ErrNoEdges.Error()
+	}
+	if e.RequiredBy != "" {
+		return fmt.Sprintf("%s is not set, but %s requires it", e.Field, e.RequiredBy)
+	}
+	return fmt.Sprintf("%s is not set", e.Field)
+}
+
+// Unwrap returns ErrNoEdges for a missing Edges field.
+func (e *MissingOptionError) Unwrap() error {
+	if e.Field == "Edges" {
+		return ErrNoEdges
+	}
+	return nil
+}
+
+var _ error = &NonMonotonicCostError[int, int]{}
+
+// NonMonotonicCostError indicates that an edge produced a cost lower than the accumulated cost
+// it was reached with, which Dijkstra's algorithm does not support.
+type NonMonotonicCostError[K comparable, C any] struct {
+	From K
+	To   K
+	Agg  C
+	Next C
+}
+
+func (e *NonMonotonicCostError[K, C]) Error() string {
+	return fmt.Sprintf("the edge lowers the accumulated cost: %v -> %v (%v -> %v)", e.From, e.To, e.Agg, e.Next)
+}
+
+var _ error = &InadmissibleHeuristicError[int, int]{}
+
+// InadmissibleHeuristicError indicates that Options.Heuristic overestimated the cost from Node to the goal.
+type InadmissibleHeuristicError[K comparable, C any] struct {
+	Node     K
+	Estimate C
+	// Actual is the cost from Node to the goal, accumulated from the initial cost.
+	Actual C
+}
+
+func (e *InadmissibleHeuristicError[K, C]) Error() string {
+	return fmt.Sprintf("the heuristic overestimates the cost to the goal: %v (%v > %v)", e.Node, e.Estimate, e.Actual)
+}
+
+var _ error = &LimitReachedError[int, int]{}
+
+// LimitReachedError indicates that the search stopped after settling Options.MaxNodes nodes.
+type LimitReachedError[K comparable, C any] struct {
+	// Costs holds the nodes settled before stopping, whose costs are final.
+	Costs map[K]Node[K, C]
+	Limit int
+}
+
+func (e *LimitReachedError[K, C]) Error() string {
+	return fmt.Sprintf("the search stopped after settling %d nodes", e.Limit)
+}
+
+var _ error = &InvalidCostError[int, int]{}
+
+// InvalidCostError indicates that an edge produced a cost rejected by Options.IsValid or Options.RejectNaN.
+type InvalidCostError[K comparable, C any] struct {
+	From K
+	To   K
+	Cost C
+}
+
+func (e *InvalidCostError[K, C]) Error() string {
+	return fmt.Sprintf("the edge produces an invalid cost: %v -> %v (%v)", e.From, e.To, e.Cost)
+}
+
+var _ error = &CyclicPathError[int, int]{}
+
+// CyclicPathError indicates that walking the predecessors from the goal loops,
+// such as with costs edited or assembled by hand.
+type CyclicPathError[K comparable, C any] struct {
+	Costs map[K]Node[K, C]
+	Goal  K
+	// Node is the first node walked twice.
+	Node K
+}
+
+func (e *CyclicPathError[K, C]) Error() string {
+	return fmt.Sprintf("the path to the goal has a cycle: %v (at %v)", e.Goal, e.Node)
+}
+
+// newCyclicPathError walks the predecessors from the goal again to find the node the cycle starts from.
+func newCyclicPathError[K comparable, C any](costs map[K]Node[K, C], goal K) error {
+	visited := make(map[K]struct{})
+	current := goal
+	for {
+		if _, ok := visited[current]; ok {
+			return &CyclicPathError[K, C]{Costs: costs, Goal: goal, Node: current}
+		}
+		visited[current] = struct{}{}
+		current = *costs[current].Prev
 	}
 }
 
+var _ error = &BrokenPathError[int]{}
+
+// BrokenPathError indicates that the goal is in the costs, but its path cannot be resolved,
+// such as with costs edited or assembled by hand.
+type BrokenPathError[K comparable] struct {
+	Goal K
+	// Node is the last node walked from the goal.
+	Node K
+	// Missing is the predecessor of Node that is not in the costs.
+	Missing K
+}
+
+func (e *BrokenPathError[K]) Error() string {
+	return fmt.Sprintf("the path to the goal is broken: %v (%v -> %v is missing)", e.Goal, e.Missing, e.Node)
+}
+
+var _ error = &NotFinalizedError[int]{}
+
+// NotFinalizedError indicates that the path to the goal goes through a node whose cost is tentative,
+// so the path may not be the shortest.
+type NotFinalizedError[K comparable] struct {
+	Goal K
+	// Node is the node of the path closest to the goal that is not finalized.
+	Node K
+}
+
+func (e *NotFinalizedError[K]) Error() string {
+	return fmt.Sprintf("the path to the goal goes through a node that is not finalized: %v (node: %v)", e.Goal, e.Node)
+}
+
 var _ error = &NotReachableError[int, int]{}
 
 // NotReachableError indicates that the specified goal cannot be reached from the start node.
@@ -171,51 +1368,69 @@ type NotReachableError[K comparable, C any] struct {
 	Start           K
 	Goal            K
 	StartingUnknown bool
+	// Goals lists the goals not reachable when several were queried, Goal being the first of them.
+	Goals []K
+	// Reason is set by Options.Diagnose to explain why the goal is not reachable.
+	Reason UnreachableReason
 }
 
 func (e *NotReachableError[K, C]) Error() string {
-	if e.StartingUnknown {
-		return fmt.Sprintf("the specified goal is not reachable from the start node: %v", e.Goal)
+	var msg string
+	if len(e.Goals) > 1 {
+		if e.StartingUnknown {
+			msg = fmt.Sprintf("the specified goals are not reachable from the start node: %v", e.Goals)
+		} else {
+			msg = fmt.Sprintf("the specified goals are not reachable from the start node: %v -> %v", e.Start, e.Goals)
+		}
+	} else if e.StartingUnknown {
+		msg = fmt.Sprintf("the specified goal is not reachable from the start node: %v", e.Goal)
+	} else {
+		msg = fmt.Sprintf("the specified goal is not reachable from the start node: %v -> %v", e.Start, e.Goal)
 	}
-	return fmt.Sprintf("the specified goal is not reachable from the start node: %v -> %v", e.Start, e.Goal)
+	if e.Reason != ReasonUnknown {
+		msg += fmt.Sprintf(" (%v)", e.Reason)
+	}
+	return msg
 }
 
 func newNotReachableError[K comparable, C any](costs map[K]Node[K, C], less func(i C, j C) bool, goal K) error {
-	start, ok := minCostNode(getKeys(costs), costs, less)
-	return &NotReachableError[K, C]{Costs: costs, Start: start, Goal: goal, StartingUnknown: !ok}
+	return newStartCache(costs, less).notReachable(goal)
 }
 
-func getKeys[K comparable, V any](collection map[K]V) []K {
-	keys := make([]K, len(collection))
-	i := 0
-	for key := range collection {
-		keys[i] = key
-		i++
-	}
-	return keys
+// startCache finds the start node of the costs, the node with the minimum cost, at most once,
+// so that repeated failed lookups against the same costs do not each scan the whole map.
+type startCache[K comparable, C any] struct {
+	costs map[K]Node[K, C]
+	less  func(i C, j C) bool
+	once  sync.Once
+	start K
+	ok    bool
 }
 
-func minCostNode[K comparable, C any](nodes []K, costs map[K]Node[K, C], less func(i C, j C) bool) (min K, ok bool) {
-	return filterMinBy(nodes, func(node K, _ int) bool {
-		_, ok := costs[node]
-		return ok
-	}, func(i K, j K) bool {
-		return less(costs[i].Cost, costs[j].Cost)
+func newStartCache[K comparable, C any](costs map[K]Node[K, C], less func(i C, j C) bool) *startCache[K, C] {
+	return &startCache[K, C]{costs: costs, less: less}
+}
+
+// notReachable returns the NotReachableError for the goal.
+func (s *startCache[K, C]) notReachable(goal K) error {
+	s.once.Do(func() {
+		s.start, s.ok = minCostNode(s.costs, s.less)
 	})
+	return &NotReachableError[K, C]{Costs: s.costs, Start: s.start, Goal: goal, StartingUnknown: !s.ok}
 }
 
-func filterMinBy[V any](collection []V, predicate func(item V, index int) bool, less func(i V, j V) bool) (min V, ok bool) {
-	for index, item := range collection {
-		if !predicate(item, index) {
-			continue
-		}
-		if !ok {
-			ok = true
-			min = item
-			continue
-		}
-		if less(item, min) {
-			min = item
+func newNotReachableErrorMulti[K comparable, C any](costs map[K]Node[K, C], less func(i C, j C) bool, goals []K) error {
+	err := newNotReachableError(costs, less, goals[0]).(*NotReachableError[K, C])
+	err.Goals = goals
+	return err
+}
+
+// minCostNode finds the node with the minimum cost, iterating the costs without copying their keys.
+func minCostNode[K comparable, C any](costs map[K]Node[K, C], less func(i C, j C) bool) (min K, ok bool) {
+	var cost C
+	for key, node := range costs {
+		if !ok || less(node.Cost, cost) {
+			min, cost, ok = key, node.Cost, true
 		}
 	}
 	return
@@ -2,7 +2,10 @@ package dijkstra_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"math"
 	"math/rand"
 	"strconv"
 	"strings"
@@ -47,6 +50,31 @@ func RandomWallGraph(cols, rows uint) map[Key]Cost {
 	return costs
 }
 
+func RandomCostGraph(rnd *rand.Rand, cols, rows uint, max Cost) map[Key]Cost {
+	costs := make(map[Key]Cost)
+	for col := range lo.Range(int(cols)) {
+		for row := range lo.Range(int(rows)) {
+			if rnd.Intn(5) == 0 {
+				continue
+			}
+			costs[Key{X: row, Y: col}] = Cost(rnd.Intn(int(max))) + 1
+		}
+	}
+	return costs
+}
+
+func Manhattan(a, b Key) Cost {
+	return Cost(lo.Ternary(a.X > b.X, a.X-b.X, b.X-a.X) + lo.Ternary(a.Y > b.Y, a.Y-b.Y, b.Y-a.Y))
+}
+
+func PathCost(graph map[Key]Cost, path []Key) Cost {
+	var total Cost
+	for _, key := range lo.Drop(path, 1) {
+		total += graph[key]
+	}
+	return total
+}
+
 func MockOptions(graph map[Key]Cost) dijkstra.Options[Key, Cost] {
 	return dijkstra.Options[Key, Cost]{
 		Accumulator: func(agg Cost, from, to Key) (next Cost, ok bool) {
@@ -107,6 +135,321 @@ func TestReachablePathFinder(t *testing.T) {
 	t.Log(path)
 }
 
+func TestSolve(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(4, 4, 1))
+	costs, resolve := options.Solve(Key{X: 0, Y: 0}, Cost(0))
+	a.Len(costs, 16)
+	path := lo.Must(resolve(Key{X: 3, Y: 3}))
+	a.Equal(Key{X: 0, Y: 0}, path[0])
+	a.Equal(int(costs[Key{X: 3, Y: 3}].Cost)+1, len(path))
+}
+
+func TestSolveNotReachable(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  1  ■  1 
+	1  1  ■  1 
+	`)
+	options := MockOptions(graph)
+	start := Key{X: 1, Y: 1}
+	costs, resolve := options.Solve(start, Cost(0))
+	for _, goal := range []Key{{X: 0, Y: 3}, {X: 1, Y: 3}} {
+		_, err := resolve(goal)
+		var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+		a.ErrorAs(err, &notReachableErr)
+		a.Equal(start, notReachableErr.Start)
+		a.Equal(goal, notReachableErr.Goal)
+		a.False(notReachableErr.StartingUnknown)
+		a.Equal(lo.T2(options.ShortestPath(costs, goal)), lo.T2(resolve(goal)))
+	}
+}
+
+func TestCreatePathFinderMemoized(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(RandomCostGraph(rand.New(rand.NewSource(5)), 10, 10, 9))
+	start := Key{X: 0, Y: 0}
+	costs := options.Dijkstra(start, Cost(0))
+	resolvePath := options.CreatePathFinder(start, Cost(0))
+	for range 2 {
+		for key := range costs {
+			path := lo.Must(resolvePath(key))
+			a.Equal(lo.Must(options.ShortestPath(costs, key)), path)
+			// The returned paths are copies of the memoized ones.
+			path[0] = key
+		}
+	}
+	_, err := resolvePath(Key{X: -1, Y: -1})
+	var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+	a.ErrorAs(err, &notReachableErr)
+}
+
+func TestCreatePathFinderWithCosts(t *testing.T) {
+	a := assert.New(t)
+	graph := RandomCostGraph(rand.New(rand.NewSource(4)), 8, 8, 9)
+	options := MockOptions(graph)
+	start, goal := Key{X: 0, Y: 0}, Key{X: 7, Y: 7}
+	costs := options.Dijkstra(start, Cost(0))
+	nodes := lo.Must(options.CreatePathFinderWithCosts(start, Cost(0))(goal))
+	a.Equal(costs[goal].Cost, nodes[len(nodes)-1].Cost)
+	a.Equal(start, nodes[0].Key)
+
+	resolvePath, resolveNodes := options.CreatePathFinders(start, Cost(0))
+	path := lo.Must(resolvePath(goal))
+	a.Equal(path, lo.Map(lo.Must(resolveNodes(goal)), func(node dijkstra.Node[Key, Cost], _ int) Key {
+		return node.Key
+	}))
+}
+
+func TestPathResolve(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(3, 3, 1))
+	var costs map[Key]dijkstra.NodeCost[Key, Cost] = options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.Equal(lo.Must(options.ShortestPath(costs, Key{X: 2, Y: 2})), lo.Must(options.PathResolve(costs, Key{X: 2, Y: 2})))
+	a.Equal(Costs2Graph(costs)[Key{X: 2, Y: 2}], Cost(4))
+	_, err := options.PathResolve(costs, Key{X: 5, Y: 5})
+	var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+	a.ErrorAs(err, &notReachableErr)
+}
+
+func TestPathResolveNotFinalized(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(1, 5, 1))
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	for _, node := range costs {
+		a.True(node.Finalized)
+	}
+	options.MaxNodes = 3
+	for _, node := range options.Dijkstra(Key{X: 0, Y: 0}, Cost(0)) {
+		a.True(node.Finalized)
+	}
+
+	tentative := costs[Key{X: 2, Y: 0}]
+	tentative.Finalized = false
+	costs[tentative.Key] = tentative
+	_, err := options.PathResolve(costs, Key{X: 4, Y: 0})
+	var notFinalizedErr *dijkstra.NotFinalizedError[Key]
+	a.ErrorAs(err, &notFinalizedErr)
+	a.Equal(dijkstra.NotFinalizedError[Key]{Goal: Key{X: 4, Y: 0}, Node: Key{X: 2, Y: 0}}, *notFinalizedErr)
+	a.NoError(lo.T2(options.PathResolve(costs, Key{X: 1, Y: 0})).B)
+	a.NoError(lo.T2(options.ShortestPath(costs, Key{X: 4, Y: 0})).B)
+}
+
+func TestShortestPathBroken(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(1, 5, 1))
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	delete(costs, Key{X: 2, Y: 0})
+	_, err := options.ShortestPath(costs, Key{X: 4, Y: 0})
+	var brokenErr *dijkstra.BrokenPathError[Key]
+	a.ErrorAs(err, &brokenErr)
+	a.Equal(dijkstra.BrokenPathError[Key]{Goal: Key{X: 4, Y: 0}, Node: Key{X: 3, Y: 0}, Missing: Key{X: 2, Y: 0}}, *brokenErr)
+	var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+	a.False(errors.As(err, &notReachableErr))
+
+	_, err = options.ShortestPath(costs, Key{X: 2, Y: 0})
+	a.ErrorAs(err, &notReachableErr)
+
+	x, y := "x", "y"
+	cycle := map[string]dijkstra.Node[string, int]{
+		x: {Key: x, Cost: 1, Prev: &y},
+		y: {Key: y, Cost: 2, Prev: &x},
+	}
+	_, err = WeightedOptions(nil).ShortestPath(cycle, x)
+	var cyclicErr *dijkstra.CyclicPathError[string, int]
+	a.ErrorAs(err, &cyclicErr)
+	a.Equal(x, cyclicErr.Node)
+
+	// The cycle does not need to go through the goal.
+	z := "z"
+	cycle[z] = dijkstra.Node[string, int]{Key: z, Cost: 3, Prev: &y}
+	_, err = WeightedOptions(nil).PathResolve(cycle, z)
+	a.ErrorAs(err, &cyclicErr)
+	a.Equal(y, cyclicErr.Node)
+}
+
+func TestPathsTo(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(6, 6, 1))
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	goals := []Key{{X: 5, Y: 5}, {X: 2, Y: 3}, {X: 9, Y: 9}, {X: 5, Y: 4}, {X: 0, Y: 0}, {X: 7, Y: 8}}
+	paths, err := options.PathsTo(costs, goals)
+	var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+	a.ErrorAs(err, &notReachableErr)
+	a.Equal(Key{X: 9, Y: 9}, notReachableErr.Goal)
+	a.Equal([]Key{{X: 9, Y: 9}, {X: 7, Y: 8}}, notReachableErr.Goals)
+	a.Equal("the specified goals are not reachable from the start node: (0, 0) -> [(9, 9) (8, 7)]", err.Error())
+	a.Len(paths, 4)
+	goals = append(goals[:2], goals[3:5]...)
+	for _, goal := range goals {
+		a.Equal(lo.Must(options.ShortestPath(costs, goal)), paths[goal], "goal %v", goal)
+	}
+
+	paths, err = options.PathsTo(costs, lo.Keys(costs))
+	a.NoError(err)
+	for goal, path := range paths {
+		a.Equal(lo.Must(options.ShortestPath(costs, goal)), path, "goal %v", goal)
+	}
+}
+
+func TestPathsToBroken(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(1, 5, 1))
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	delete(costs, Key{X: 2, Y: 0})
+	paths, err := options.PathsTo(costs, []Key{{X: 1, Y: 0}, {X: 4, Y: 0}})
+	var brokenErr *dijkstra.BrokenPathError[Key]
+	a.ErrorAs(err, &brokenErr)
+	a.Equal(dijkstra.BrokenPathError[Key]{Goal: Key{X: 4, Y: 0}, Node: Key{X: 3, Y: 0}, Missing: Key{X: 2, Y: 0}}, *brokenErr)
+	var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+	a.False(errors.As(err, &notReachableErr))
+	a.Nil(paths)
+
+	x, y := "x", "y"
+	cycle := map[string]dijkstra.Node[string, int]{
+		x: {Key: x, Cost: 1, Prev: &y},
+		y: {Key: y, Cost: 2, Prev: &x},
+	}
+	_, err = WeightedOptions(nil).PathsTo(cycle, []string{x})
+	var cyclicErr *dijkstra.CyclicPathError[string, int]
+	a.ErrorAs(err, &cyclicErr)
+	a.Equal(x, cyclicErr.Node)
+}
+
+func TestDijkstraTo(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(10, 10, 1))
+	start, goal := Key{X: 0, Y: 0}, Key{X: 2, Y: 2}
+	costs := options.DijkstraTo(start, goal, Cost(0))
+	a.Less(len(costs), 100)
+	a.Equal(Cost(4), costs[goal].Cost)
+	a.Len(lo.Must(options.ShortestPath(costs, goal)), 5)
+	_, err := options.ShortestPath(costs, Key{X: 9, Y: 9})
+	var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+	a.ErrorAs(err, &notReachableErr)
+}
+
+func TestDijkstraToFrontier(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(10, 10, 1))
+	start, goal := Key{X: 0, Y: 0}, Key{X: 2, Y: 2}
+	costs, frontier := options.DijkstraToFrontier(start, goal, Cost(0))
+	a.Equal(options.DijkstraTo(start, goal, Cost(0)), costs)
+	a.NotEmpty(frontier)
+	for i, key := range frontier {
+		a.NotContains(costs, key)
+		a.Equal(1, lo.Count(frontier, key))
+		// In the order they would have been settled, by cost on a flat grid.
+		if i > 0 {
+			a.LessOrEqual(frontier[i-1].X+frontier[i-1].Y, key.X+key.Y)
+		}
+	}
+
+	_, frontier = options.DijkstraToFrontier(start, Key{X: 20, Y: 20}, Cost(0))
+	a.Empty(frontier)
+}
+
+func TestDijkstraMulti(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(7, 1, 1))
+	left, right, middle := Key{X: 0, Y: 0}, Key{X: 0, Y: 6}, Key{X: 0, Y: 3}
+	costs := options.DijkstraMulti([]Key{left, right}, Cost(0))
+	a.Len(costs, 7)
+	a.Equal(Cost(2), costs[Key{X: 0, Y: 4}].Cost)
+	a.Equal(right, lo.Must(options.ShortestPath(costs, Key{X: 0, Y: 4}))[0])
+	a.Equal(Cost(3), costs[middle].Cost)
+	for i := 0; i < 10; i++ {
+		a.Equal(left, lo.Must(options.ShortestPath(options.DijkstraMulti([]Key{left, right}, Cost(0)), middle))[0])
+		a.Equal(right, lo.Must(options.ShortestPath(options.DijkstraMulti([]Key{right, left}, Cost(0)), middle))[0])
+	}
+}
+
+func TestDijkstraWithin(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(10, 1, 1))
+	costs := options.DijkstraWithin(Key{X: 0, Y: 0}, Cost(0), Cost(3))
+	// The budget is inclusive: the node costing exactly 3 is reached.
+	a.Equal(map[Key]Cost{{X: 0, Y: 0}: 0, {X: 0, Y: 1}: 1, {X: 0, Y: 2}: 2, {X: 0, Y: 3}: 3}, Costs2Graph(costs))
+	a.Len(lo.Must(options.ShortestPath(costs, Key{X: 0, Y: 3})), 4)
+
+	costs = options.DijkstraWithin(Key{X: 0, Y: 0}, Cost(0), Cost(0))
+	a.Len(costs, 1)
+}
+
+func TestWeightedEdges(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  ■  1  1 
+	1  1  5  ■ 
+	■  3  1  1 
+	`)
+	options := MockOptions(graph)
+	expected := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	edges := func(from Key) []dijkstra.Edge[Key, Cost] {
+		return lo.Map(options.Edges(from), func(to Key, _ int) dijkstra.Edge[Key, Cost] {
+			return dijkstra.Edge[Key, Cost]{To: to, Weight: graph[to]}
+		})
+	}
+	add := func(agg, weight Cost) Cost { return agg + weight }
+	a.Equal(Costs2Graph(expected), Costs2Graph(dijkstra.DijkstraWeighted(Key{X: 0, Y: 0}, Cost(0), options.Less, add, edges)))
+
+	weighted := dijkstra.Options[Key, Cost]{Less: options.Less, Add: add, WeightedEdges: edges}
+	costs := weighted.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.Equal(Costs2Graph(expected), Costs2Graph(costs))
+	a.Equal(lo.Must(options.ShortestPath(expected, Key{X: 2, Y: 3})), lo.Must(weighted.ShortestPath(costs, Key{X: 2, Y: 3})))
+}
+
+func TestDijkstraSeq(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(5, 5, 1))
+	var last Cost
+	count := 0
+	for key, node := range options.DijkstraSeq(Key{X: 0, Y: 0}, Cost(0)) {
+		a.Equal(key, node.Key)
+		a.GreaterOrEqual(node.Cost, last)
+		last = node.Cost
+		count++
+	}
+	a.Equal(25, count)
+
+	expanded := 0
+	edges := options.Edges
+	options.Edges = func(p Key) []Key {
+		expanded++
+		return edges(p)
+	}
+	var found Key
+	for key, node := range options.DijkstraSeq(Key{X: 0, Y: 0}, Cost(0)) {
+		if node.Cost == 2 {
+			found = key
+			break
+		}
+	}
+	a.Equal(Cost(2), Cost(found.X+found.Y))
+	a.Less(expanded, 25)
+}
+
+func TestDijkstraUntil(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  1  1  1 
+	1  ■  ■  1 
+	1  1  5  1 
+	`)
+	options := MockOptions(graph)
+	costs, found := options.DijkstraUntil(Key{X: 0, Y: 0}, Cost(0), func(key Key, node dijkstra.Node[Key, Cost]) bool {
+		return graph[key] == 5
+	})
+	a.NotNil(found)
+	a.Equal(Key{X: 2, Y: 2}, *found)
+	a.Len(lo.Must(options.ShortestPath(costs, *found)), 5)
+
+	_, found = options.DijkstraUntil(Key{X: 0, Y: 0}, Cost(0), func(key Key, node dijkstra.Node[Key, Cost]) bool {
+		return graph[key] == 9
+	})
+	a.Nil(found)
+}
+
 func TestUnreachable(t *testing.T) {
 	a := assert.New(t)
 	graph := Text2Graph(`
@@ -153,6 +496,846 @@ func TestOverGraphEdges(t *testing.T) {
 	}
 }
 
+func TestOverGraphEdgesSettled(t *testing.T) {
+	a := assert.New(t)
+	graph := FlatGraph(10, 8, 1)
+	options := MockOptions(graph)
+	want := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	// Edges is called right after a node is settled.
+	settled := make(map[Key]bool)
+	options.Edges = func(p Key) []Key {
+		settled[p] = true
+		return []Key{
+			{X: p.X, Y: p.Y + 1},
+			{X: p.X, Y: p.Y - 1},
+			{X: p.X + 1, Y: p.Y},
+			{X: p.X - 1, Y: p.Y},
+		}
+	}
+	accumulator := options.Accumulator
+	options.Accumulator = func(agg Cost, from, to Key) (Cost, bool) {
+		a.False(settled[to], "the cost into the settled node %v is computed", to)
+		return accumulator(agg, from, to)
+	}
+	costs, stats := options.DijkstraStats(Key{X: 0, Y: 0}, Cost(0))
+	a.Equal(want, costs)
+	a.Equal(len(costs), stats.Pushes)
+}
+
+func TestTiebreak(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(6, 6, 1))
+	edges := options.Edges
+	rnd := rand.New(rand.NewSource(1))
+	// Shuffled edges reach the nodes in a different order on every run.
+	options.Edges = func(p Key) []Key {
+		dest := edges(p)
+		rnd.Shuffle(len(dest), func(i, j int) { dest[i], dest[j] = dest[j], dest[i] })
+		return dest
+	}
+	options.Tiebreak = func(i, j Key) bool {
+		return i.X < j.X || (i.X == j.X && i.Y < j.Y)
+	}
+	start, goal := Key{X: 0, Y: 0}, Key{X: 5, Y: 5}
+	want := lo.Must(options.ShortestPath(options.Dijkstra(start, Cost(0)), goal))
+	for i := 0; i < 100; i++ {
+		a.Equal(want, lo.Must(options.ShortestPath(options.Dijkstra(start, Cost(0)), goal)))
+	}
+}
+
+func TestPayload(t *testing.T) {
+	a := assert.New(t)
+	graph := FlatGraph(3, 3, 1)
+	options := MockOptions(graph)
+	options.Payload = func(prev *Key, from, to Key, cost Cost) any {
+		return Key{X: to.X - from.X, Y: to.Y - from.Y}
+	}
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.Nil(costs[Key{X: 0, Y: 0}].Data)
+	for key, node := range costs {
+		if node.Prev == nil {
+			continue
+		}
+		a.Equal(Key{X: key.X - node.Prev.X, Y: key.Y - node.Prev.Y}, node.Data)
+	}
+}
+
+func WeightedOptions(graph map[string]map[string]int) dijkstra.Options[string, int] {
+	return dijkstra.Options[string, int]{
+		Accumulator: func(agg int, from, to string) (int, bool) {
+			weight, ok := graph[from][to]
+			return agg + weight, ok
+		},
+		Less: func(i, j int) bool {
+			return i < j
+		},
+		Edges: func(from string) []string {
+			return lo.Keys(graph[from])
+		},
+	}
+}
+
+func TestReopenClosed(t *testing.T) {
+	a := assert.New(t)
+	graph := map[string]map[string]int{
+		"a": {"b": 1, "c": 5},
+		"c": {"b": -10},
+		"b": {"d": 1},
+	}
+	options := WeightedOptions(graph)
+	costs := options.Dijkstra("a", 0)
+	a.Equal(1, costs["b"].Cost)
+	a.Equal(2, costs["d"].Cost)
+
+	options.ReopenClosed = true
+	costs = options.Dijkstra("a", 0)
+	a.Equal(-5, costs["b"].Cost)
+	a.Equal(-4, costs["d"].Cost)
+	a.Equal([]string{"a", "c", "b", "d"}, lo.Must(options.ShortestPath(costs, "d")))
+}
+
+func TestResettle(t *testing.T) {
+	a := assert.New(t)
+	graph := map[string]map[string]int{
+		"a": {"b": 1, "c": 5},
+		"c": {"b": -10},
+		"b": {"d": 1},
+	}
+	options := WeightedOptions(graph)
+	var resettled []string
+	options.Resettle = func(node string, oldCost, newCost int) bool {
+		resettled = append(resettled, node)
+		return node != "d"
+	}
+	costs := options.Dijkstra("a", 0)
+	a.Equal([]string{"b", "d"}, resettled)
+	a.Equal(-5, costs["b"].Cost)
+	a.Equal(2, costs["d"].Cost)
+}
+
+type AdjacentKey int
+
+func (k AdjacentKey) Adjacent() []AdjacentKey {
+	if k >= 3 {
+		return nil
+	}
+	return []AdjacentKey{k + 1}
+}
+
+// WeightedKey is a node of the graph 0 -> 1 -> 3 and 0 -> 2 -> 3 weighted by the destination.
+type WeightedKey int
+
+func (k WeightedKey) AdjacentWeighted() []dijkstra.Edge[WeightedKey, Cost] {
+	switch k {
+	case 0:
+		return []dijkstra.Edge[WeightedKey, Cost]{{To: 1, Weight: 5}, {To: 2, Weight: 1}}
+	case 1, 2:
+		return []dijkstra.Edge[WeightedKey, Cost]{{To: 3, Weight: 1}}
+	}
+	return nil
+}
+
+func TestAdjacentWeighted(t *testing.T) {
+	a := assert.New(t)
+	options := dijkstra.Options[WeightedKey, Cost]{
+		Less: func(i, j Cost) bool { return i < j },
+	}
+	var missingErr *dijkstra.MissingOptionError
+	a.ErrorAs(options.Validate(), &missingErr)
+	a.Equal("Add", missingErr.Field)
+
+	options.Add = func(agg, weight Cost) Cost { return agg + weight }
+	costs := options.Dijkstra(0, 0)
+	a.Len(costs, 4)
+	a.Equal(Cost(2), costs[3].Cost)
+	a.Equal([]WeightedKey{0, 2, 3}, lo.Must(options.ShortestPath(costs, 3)))
+}
+
+func TestTryDijkstra(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(2, 2, 1))
+	options.Edges = nil
+	_, err := options.TryDijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.ErrorIs(err, dijkstra.ErrNoEdges)
+
+	costs, err := dijkstra.Options[AdjacentKey, Cost]{
+		Accumulator: func(agg Cost, from, to AdjacentKey) (Cost, bool) {
+			return agg + 1, true
+		},
+		Less: func(i, j Cost) bool { return i < j },
+	}.TryDijkstra(0, 0)
+	a.NoError(err)
+	a.Len(costs, 4)
+	a.Equal(Cost(3), costs[3].Cost)
+}
+
+func TestNodePenalty(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(5, 5, 1))
+	start, goal := Key{X: 2, Y: 0}, Key{X: 2, Y: 4}
+	a.Len(lo.Must(options.ShortestPath(options.Dijkstra(start, Cost(0)), goal)), 5)
+
+	zone := map[Key]bool{{X: 2, Y: 1}: true, {X: 2, Y: 2}: true, {X: 2, Y: 3}: true}
+	options.Add = func(a, b Cost) Cost { return a + b }
+	options.NodePenalty = func(node Key) Cost {
+		return lo.Ternary[Cost](zone[node], 10, 0)
+	}
+	costs := options.Dijkstra(start, Cost(0))
+	path := lo.Must(options.ShortestPath(costs, goal))
+	a.Equal(Cost(6), costs[goal].Cost)
+	a.Len(path, 7)
+	for _, key := range path {
+		a.False(zone[key], key)
+	}
+}
+
+func TestPreferLowerPrev(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(4, 4, 1))
+	options.PreferLowerPrev = func(i, j Key) bool {
+		return i.X < j.X || (i.X == j.X && i.Y < j.Y)
+	}
+	for i := 0; i < 10; i++ {
+		path := lo.Must(options.ShortestPath(options.Dijkstra(Key{X: 0, Y: 0}, Cost(0)), Key{X: 3, Y: 3}))
+		a.Equal([]Key{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 0, Y: 3}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}}, path)
+	}
+}
+
+func TestFIFOTies(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(6, 6, 1))
+	start := Key{X: 0, Y: 0}
+
+	// Breadth-first search with the same neighbor order.
+	queue := []Key{start}
+	visited := map[Key]bool{start: true}
+	for i := 0; i < len(queue); i++ {
+		for _, to := range options.Edges(queue[i]) {
+			if !visited[to] {
+				visited[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	var expanded []Key
+	edges := options.Edges
+	options.Edges = func(p Key) []Key {
+		expanded = append(expanded, p)
+		return edges(p)
+	}
+	options.FIFOTies = true
+	costs := options.Dijkstra(start, Cost(0))
+	a.Equal(queue, expanded)
+	a.Equal(Costs2Graph(MockOptions(FlatGraph(6, 6, 1)).Dijkstra(start, Cost(0))), Costs2Graph(costs))
+}
+
+func BenchmarkDijkstraFull(b *testing.B) {
+	options := MockOptions(FlatGraph(200, 200, 1))
+	for i := 0; i < b.N; i++ {
+		options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	}
+}
+
+func BenchmarkDijkstraFlatGraph300(b *testing.B) {
+	options := MockOptions(FlatGraph(300, 300, 1))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	}
+}
+
+func BenchmarkShortestPathCorridor(b *testing.B) {
+	options := MockOptions(FlatGraph(1, 1000, 1))
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lo.Must(options.ShortestPath(costs, Key{X: 999, Y: 0}))
+	}
+}
+
+func BenchmarkCreatePathFinder(b *testing.B) {
+	options := MockOptions(FlatGraph(100, 100, 1))
+	start := Key{X: 0, Y: 0}
+	costs := options.Dijkstra(start, Cost(0))
+	goals := lo.Keys(costs)
+	b.Run("naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, goal := range goals {
+				lo.Must(options.ShortestPath(costs, goal))
+			}
+		}
+	})
+	b.Run("memoized", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			// Each iteration starts from an empty cache.
+			b.StopTimer()
+			_, resolvePath := options.Solve(start, Cost(0))
+			b.StartTimer()
+			for _, goal := range goals {
+				lo.Must(resolvePath(goal))
+			}
+		}
+	})
+}
+
+func BenchmarkResolveNotReachable(b *testing.B) {
+	options := MockOptions(FlatGraph(200, 200, 1))
+	costs, resolve := options.Solve(Key{X: 0, Y: 0}, Cost(0))
+	b.Run("PathFinder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = resolve(Key{X: -1, Y: -1})
+		}
+	})
+	b.Run("ShortestPath", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = options.ShortestPath(costs, Key{X: -1, Y: -1})
+		}
+	})
+}
+
+func BenchmarkDijkstraTo(b *testing.B) {
+	options := MockOptions(FlatGraph(200, 200, 1))
+	for i := 0; i < b.N; i++ {
+		options.DijkstraTo(Key{X: 0, Y: 0}, Key{X: 20, Y: 20}, Cost(0))
+	}
+}
+
+func TestDijkstraContext(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(5, 5, 1))
+	costs, err := options.DijkstraContext(context.Background(), Key{X: 0, Y: 0}, Cost(0))
+	a.NoError(err)
+	a.Len(costs, 25)
+
+	// An unbounded graph never finishes on its own.
+	options.Accumulator = func(agg Cost, from, to Key) (Cost, bool) {
+		return agg + 1, true
+	}
+	options.Edges = func(p Key) []Key {
+		return []Key{{X: p.X, Y: p.Y + 1}, {X: p.X, Y: p.Y - 1}, {X: p.X + 1, Y: p.Y}, {X: p.X - 1, Y: p.Y}}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	costs, err = options.DijkstraContext(ctx, Key{X: 0, Y: 0}, Cost(0))
+	a.ErrorIs(err, context.DeadlineExceeded)
+	a.NotEmpty(costs)
+	for key := range costs {
+		path := lo.Must(options.ShortestPath(costs, key))
+		a.Equal(int(costs[key].Cost)+1, len(path))
+		break
+	}
+}
+
+func TestDijkstraContextCallbacks(t *testing.T) {
+	a := assert.New(t)
+	graph := FlatGraph(5, 5, 1)
+	mock := MockOptions(graph)
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "db")
+	received := make(map[any]bool)
+	options := dijkstra.Options[Key, Cost]{
+		Less: mock.Less,
+		EdgesCtx: func(ctx context.Context, from Key) ([]Key, error) {
+			received[ctx.Value(ctxKey{})] = true
+			return mock.Edges(from), nil
+		},
+		AccumulatorCtx: func(ctx context.Context, agg Cost, from, to Key) (Cost, bool, error) {
+			received[ctx.Value(ctxKey{})] = true
+			cost, ok := mock.Accumulator(agg, from, to)
+			return cost, ok, nil
+		},
+	}
+	costs, err := options.DijkstraContext(ctx, Key{X: 0, Y: 0}, Cost(0))
+	a.NoError(err)
+	a.Equal(mock.Dijkstra(Key{X: 0, Y: 0}, Cost(0)), costs)
+	a.Equal(map[any]bool{"db": true}, received)
+
+	errLookup := errors.New("lookup failed")
+	edges := options.EdgesCtx
+	options.EdgesCtx = func(ctx context.Context, from Key) ([]Key, error) {
+		if from == (Key{X: 2, Y: 2}) {
+			return nil, errLookup
+		}
+		return edges(ctx, from)
+	}
+	costs, err = options.DijkstraContext(ctx, Key{X: 0, Y: 0}, Cost(0))
+	a.ErrorIs(err, errLookup)
+	a.Contains(costs, Key{X: 2, Y: 2})
+	a.Panics(func() { options.Dijkstra(Key{X: 0, Y: 0}, Cost(0)) })
+
+	options.EdgesCtx = edges
+	options.AccumulatorCtx = func(ctx context.Context, agg Cost, from, to Key) (Cost, bool, error) {
+		return agg, false, errLookup
+	}
+	_, err = options.TryDijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.ErrorIs(err, errLookup)
+}
+
+func TestValidate(t *testing.T) {
+	a := assert.New(t)
+	a.NoError(MockOptions(FlatGraph(2, 2, 1)).Validate())
+	for field, options := range map[string]dijkstra.Options[Key, Cost]{
+		"Less":        {Accumulator: MockOptions(nil).Accumulator, Edges: MockOptions(nil).Edges},
+		"Accumulator": {Less: MockOptions(nil).Less, Edges: MockOptions(nil).Edges},
+		"Edges":       {Less: MockOptions(nil).Less, Accumulator: MockOptions(nil).Accumulator},
+	} {
+		err := options.Validate()
+		var missingErr *dijkstra.MissingOptionError
+		a.ErrorAs(err, &missingErr)
+		a.Equal(field, missingErr.Field)
+		a.PanicsWithError(err.Error(), func() {
+			options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+		})
+		_, tryErr := options.TryDijkstra(Key{X: 0, Y: 0}, Cost(0))
+		a.Equal(err, tryErr)
+	}
+
+	options := MockOptions(FlatGraph(2, 2, 1))
+	options.NodePenalty = func(Key) Cost { return 1 }
+	var missingErr *dijkstra.MissingOptionError
+	a.ErrorAs(options.Validate(), &missingErr)
+	a.Equal("Add", missingErr.Field)
+	a.Equal("NodePenalty", missingErr.RequiredBy)
+	a.EqualError(missingErr, "Add is not set, but NodePenalty requires it")
+
+	a.NoError(dijkstra.Options[AdjacentKey, Cost]{
+		Accumulator: func(agg Cost, from, to AdjacentKey) (Cost, bool) { return agg + 1, true },
+		Less:        func(i, j Cost) bool { return i < j },
+	}.Validate())
+}
+
+func TestStrictMonotonic(t *testing.T) {
+	a := assert.New(t)
+	options := WeightedOptions(map[string]map[string]int{
+		"a": {"b": 1, "c": 5},
+		"c": {"b": -10},
+		"b": {"d": 1},
+	})
+	_, err := options.TryDijkstra("a", 0)
+	a.NoError(err)
+
+	options.StrictMonotonic = true
+	_, err = options.TryDijkstra("a", 0)
+	var nonMonotonicErr *dijkstra.NonMonotonicCostError[string, int]
+	a.ErrorAs(err, &nonMonotonicErr)
+	a.Equal(dijkstra.NonMonotonicCostError[string, int]{From: "c", To: "b", Agg: 5, Next: -5}, *nonMonotonicErr)
+	a.Panics(func() {
+		options.Dijkstra("a", 0)
+	})
+
+	grid := MockOptions(FlatGraph(3, 3, 1))
+	grid.StrictMonotonic = true
+	_, err = grid.TryDijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.NoError(err)
+}
+
+func TestHooks(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  3  1 
+	1  ■  1 
+	1  1  1 
+	`)
+	options := MockOptions(graph)
+	var order []Key
+	options.OnFinalize = func(key Key, node dijkstra.Node[Key, Cost]) {
+		a.Equal(key, node.Key)
+		order = append(order, key)
+	}
+	accepted := make(map[Key]Cost)
+	rejected := 0
+	options.OnRelax = func(from, to Key, newCost Cost, ok bool) {
+		if ok {
+			accepted[to] = newCost
+		} else {
+			rejected++
+		}
+	}
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.Len(order, len(costs))
+	a.Equal(Key{X: 0, Y: 0}, order[0])
+	for i := 1; i < len(order); i++ {
+		a.LessOrEqual(costs[order[i-1]].Cost, costs[order[i]].Cost)
+	}
+	// The last cost accepted for each node is the one it is settled with.
+	delete(costs, Key{X: 0, Y: 0})
+	a.Equal(Costs2Graph(costs), accepted)
+	a.Positive(rejected)
+}
+
+func TestParallelEdges(t *testing.T) {
+	a := assert.New(t)
+	graph := RandomCostGraph(rand.New(rand.NewSource(3)), 20, 20, 9)
+	options := MockOptions(graph)
+	options.Tiebreak = func(i, j Key) bool {
+		return i.X < j.X || (i.X == j.X && i.Y < j.Y)
+	}
+	expected := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	options.ParallelEdges = true
+	a.Equal(expected, options.Dijkstra(Key{X: 0, Y: 0}, Cost(0)))
+}
+
+func BenchmarkParallelEdges(b *testing.B) {
+	options := MockOptions(FlatGraph(20, 20, 1))
+	accumulator := options.Accumulator
+	// A slow accumulator, such as a geometric computation.
+	options.Accumulator = func(agg Cost, from, to Key) (Cost, bool) {
+		time.Sleep(20 * time.Microsecond)
+		return accumulator(agg, from, to)
+	}
+	for _, parallel := range []bool{false, true} {
+		options.ParallelEdges = parallel
+		b.Run(fmt.Sprint("Parallel=", parallel), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+			}
+		})
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	a := assert.New(t)
+	graph := FlatGraph(3, 3, 1)
+	options := MockOptions(graph)
+	// Closing the middle column leaves the bottom row as the only way across.
+	closed := map[Key]bool{{X: 0, Y: 1}: true, {X: 1, Y: 1}: true}
+	options.Blocked = func(node Key) bool {
+		return closed[node]
+	}
+	start, goal := Key{X: 0, Y: 0}, Key{X: 0, Y: 2}
+	costs := options.Dijkstra(start, Cost(0))
+	a.Len(costs, 7)
+	a.NotContains(costs, Key{X: 1, Y: 1})
+	a.Equal(Cost(6), costs[goal].Cost)
+
+	closed[start] = true
+	costs = options.Dijkstra(start, Cost(0))
+	a.Empty(costs)
+	for key := range graph {
+		_, err := options.ShortestPath(costs, key)
+		var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+		a.ErrorAs(err, &notReachableErr)
+	}
+}
+
+func TestStartValid(t *testing.T) {
+	a := assert.New(t)
+	graph := FlatGraph(3, 3, 1)
+	options := MockOptions(graph)
+	options.StartValid = func(start Key) bool {
+		_, ok := graph[start]
+		return ok
+	}
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.Len(costs, 9)
+
+	off := Key{X: 5, Y: 5}
+	costs, err := options.TryDijkstra(off, Cost(0))
+	var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+	a.ErrorAs(err, &notReachableErr)
+	a.True(notReachableErr.StartingUnknown)
+	a.Equal(dijkstra.StartInvalid, notReachableErr.Reason)
+	a.Empty(costs)
+	costs = options.Dijkstra(off, Cost(0))
+	a.Empty(costs)
+	_, err = options.ShortestPath(costs, Key{X: 1, Y: 1})
+	a.ErrorAs(err, &notReachableErr)
+	a.True(notReachableErr.StartingUnknown)
+
+	a.Len(options.DijkstraMulti([]Key{off, {X: 0, Y: 0}}, Cost(0)), 9)
+}
+
+func TestMaxNodes(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(3, 3, 1))
+	options.MaxNodes = 9
+	costs, err := options.TryDijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.NoError(err)
+	a.Len(costs, 9)
+
+	// An unbounded graph never finishes on its own.
+	options.Accumulator = func(agg Cost, from, to Key) (Cost, bool) {
+		return agg + 1, true
+	}
+	options.Edges = func(p Key) []Key {
+		return []Key{{X: p.X, Y: p.Y + 1}, {X: p.X, Y: p.Y - 1}, {X: p.X + 1, Y: p.Y}, {X: p.X - 1, Y: p.Y}}
+	}
+	options.MaxNodes = 100
+	costs, err = options.TryDijkstra(Key{X: 0, Y: 0}, Cost(0))
+	var limitErr *dijkstra.LimitReachedError[Key, Cost]
+	a.ErrorAs(err, &limitErr)
+	a.Len(costs, 100)
+	a.Equal(costs, limitErr.Costs)
+	a.Equal(costs, options.Dijkstra(Key{X: 0, Y: 0}, Cost(0)))
+}
+
+func TestOnProgress(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(30, 30, 1))
+	var reports [][2]int
+	options.OnProgress = func(finalized, bound int) {
+		reports = append(reports, [2]int{finalized, bound})
+	}
+	options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.Empty(reports)
+
+	options.MaxNodes = 200
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.Equal([][2]int{{64, 200}, {128, 200}, {192, 200}, {200, 200}}, reports)
+	a.Equal(len(costs), reports[len(reports)-1][0])
+
+	reports = nil
+	options.MaxNodes = 0
+	costs = options.DijkstraWithin(Key{X: 0, Y: 0}, Cost(0), 10)
+	last := reports[len(reports)-1]
+	a.Equal([2]int{len(costs), len(costs)}, last)
+	for _, report := range reports {
+		a.LessOrEqual(report[0], report[1])
+	}
+}
+
+// Labeled is a grid key whose Label describes the path it was reached by, without affecting its identity.
+type Labeled struct {
+	Pos   Key
+	Label string
+}
+
+// LabeledOptions creates options over the grid graph whose edges label the keys with their paths.
+// Without Canonical, every path reaches a distinct key.
+func LabeledOptions(graph map[Key]Cost) dijkstra.Options[Labeled, Cost] {
+	grid := MockOptions(graph)
+	return dijkstra.Options[Labeled, Cost]{
+		Accumulator: func(agg Cost, from, to Labeled) (Cost, bool) {
+			return grid.Accumulator(agg, from.Pos, to.Pos)
+		},
+		Less: grid.Less,
+		Edges: func(from Labeled) []Labeled {
+			return lo.Map(grid.Edges(from.Pos), func(to Key, _ int) Labeled {
+				return Labeled{Pos: to, Label: from.Label + to.String()}
+			})
+		},
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	a := assert.New(t)
+	options := LabeledOptions(FlatGraph(3, 3, 1))
+	options.MaxNodes = 100
+	start := Labeled{Pos: Key{X: 0, Y: 0}, Label: "start"}
+	// Every path reaches a distinct key, so the search never runs out of nodes.
+	a.Len(options.Dijkstra(start, Cost(0)), 100)
+
+	options.Canonical = func(key Labeled) Labeled {
+		return Labeled{Pos: key.Pos}
+	}
+	costs := options.Dijkstra(start, Cost(0))
+	a.Len(costs, 9)
+	a.Contains(costs, Labeled{Pos: Key{X: 0, Y: 0}})
+	goal := Labeled{Pos: Key{X: 2, Y: 2}, Label: "goal"}
+	path := lo.Must(options.ShortestPath(costs, goal))
+	a.Len(path, 5)
+	a.Equal(Cost(4), lo.Must(options.PathCost(costs, goal)))
+	a.Len(options.DijkstraTo(start, goal, Cost(0)), 9)
+}
+
+func TestCanonicalKeys(t *testing.T) {
+	graph := FlatGraph(5, 5, 1)
+	options := LabeledOptions(graph)
+	options.Canonical = func(key Labeled) Labeled {
+		return Labeled{Pos: key.Pos}
+	}
+	start := Labeled{Pos: Key{X: 0, Y: 0}, Label: "start"}
+	goal := Labeled{Pos: Key{X: 2, Y: 2}, Label: "goal"}
+	canonicalGoal := Labeled{Pos: goal.Pos}
+	t.Run("ShortestPathBetween", func(t *testing.T) {
+		a := assert.New(t)
+		path, cost, err := options.ShortestPathBetween(start, goal, Cost(0))
+		a.NoError(err)
+		a.Len(path, 5)
+		a.Equal(Cost(4), cost)
+	})
+	t.Run("AStar", func(t *testing.T) {
+		a := assert.New(t)
+		options := options
+		options.Add = func(g, h Cost) Cost { return g + h }
+		options.Heuristic = func(from, goal Labeled) Cost {
+			return Manhattan(from.Pos, goal.Pos)
+		}
+		costs := options.AStar(start, Labeled{Pos: Key{X: 0, Y: 2}, Label: "goal"}, Cost(0))
+		a.Equal(Cost(2), costs[Labeled{Pos: Key{X: 0, Y: 2}}].Cost)
+		a.Less(len(costs), len(graph))
+	})
+	t.Run("PathsTo", func(t *testing.T) {
+		a := assert.New(t)
+		paths, err := options.PathsTo(options.Dijkstra(start, Cost(0)), []Labeled{goal})
+		a.NoError(err)
+		a.Len(paths[canonicalGoal], 5)
+	})
+	t.Run("CreatePathFinder", func(t *testing.T) {
+		a := assert.New(t)
+		a.Len(lo.Must(options.CreatePathFinder(start, Cost(0))(goal)), 5)
+	})
+	t.Run("NearestGoal", func(t *testing.T) {
+		a := assert.New(t)
+		nearest, path, cost, err := options.NearestGoal(start, Cost(0), []Labeled{goal})
+		a.NoError(err)
+		a.Equal(canonicalGoal, nearest)
+		a.Len(path, 5)
+		a.Equal(Cost(4), cost)
+	})
+	t.Run("Farthest", func(t *testing.T) {
+		a := assert.New(t)
+		single := LabeledOptions(FlatGraph(1, 1, 1))
+		single.Canonical = options.Canonical
+		// Only the start node is reachable, and it is not considered.
+		_, _, ok := single.Farthest(start, Cost(0))
+		a.False(ok)
+		farthest, cost, ok := options.Farthest(start, Cost(0))
+		a.True(ok)
+		a.Equal(Labeled{Pos: Key{X: 4, Y: 4}}, farthest)
+		a.Equal(Cost(8), cost)
+	})
+	t.Run("AllShortestPaths", func(t *testing.T) {
+		a := assert.New(t)
+		paths, err := options.AllShortestPaths(options.DijkstraAllPaths(start, Cost(0)), goal)
+		a.NoError(err)
+		a.Len(paths, 6)
+	})
+	t.Run("AllPairs", func(t *testing.T) {
+		a := assert.New(t)
+		all := options.AllPairs([]Labeled{start}, Cost(0))
+		a.Len(all[Labeled{Pos: start.Pos}], len(graph))
+		a.Equal(Cost(4), options.SubsetDistances([]Labeled{start, goal}, Cost(0))[Labeled{Pos: start.Pos}][canonicalGoal])
+	})
+	t.Run("Diagnose", func(t *testing.T) {
+		a := assert.New(t)
+		costs, err := options.Diagnose(start, goal, Cost(0))
+		a.NoError(err)
+		a.Equal(Cost(4), costs[canonicalGoal].Cost)
+	})
+	t.Run("BidirectionalAStar", func(t *testing.T) {
+		a := assert.New(t)
+		options := options
+		options.ReverseEdges = options.Edges
+		path, cost, err := options.BidirectionalAStar(start, goal, Cost(0), func(a, b Labeled) Cost {
+			return Manhattan(a.Pos, b.Pos)
+		}, func(g, h Cost) Cost { return g + h })
+		a.NoError(err)
+		a.Len(path, 5)
+		a.Equal(Cost(4), cost)
+	})
+	t.Run("UpdateEdge", func(t *testing.T) {
+		a := assert.New(t)
+		graph := FlatGraph(5, 5, 1)
+		options := LabeledOptions(graph)
+		options.Canonical = func(key Labeled) Labeled {
+			return Labeled{Pos: key.Pos}
+		}
+		solver := options.NewSolver()
+		solver.Run(start, Cost(0))
+		graph[Key{X: 1, Y: 0}] = 5
+		costOf := func(node dijkstra.Node[Labeled, Cost], _ Labeled) Cost { return node.Cost }
+		expected := lo.MapValues(options.Dijkstra(start, Cost(0)), costOf)
+		a.Equal(expected, lo.MapValues(solver.UpdateEdge(Labeled{Pos: Key{X: 1, Y: 0}, Label: "changed"}), costOf))
+	})
+}
+
+func TestRejectNaN(t *testing.T) {
+	a := assert.New(t)
+	options := dijkstra.StringGraphOptions(map[string]map[string]float64{
+		"a": {"b": 1, "c": math.NaN()},
+		"c": {"d": 1},
+		"b": {"e": math.Inf(1)},
+	})
+	costs, err := options.TryDijkstra("a", 0)
+	a.NoError(err)
+	a.True(math.IsNaN(costs["c"].Cost))
+
+	options.RejectNaN = true
+	_, err = options.TryDijkstra("a", 0)
+	var invalidErr *dijkstra.InvalidCostError[string, float64]
+	a.ErrorAs(err, &invalidErr)
+	a.Equal("a", invalidErr.From)
+	a.Equal("c", invalidErr.To)
+	a.Panics(func() {
+		options.Dijkstra("a", 0)
+	})
+
+	// +Inf is a valid cost unless rejected.
+	options = dijkstra.StringGraphOptions(map[string]map[string]float64{
+		"a": {"b": 1},
+		"b": {"e": math.Inf(1)},
+	})
+	costs, err = options.TryDijkstra("a", 0)
+	a.NoError(err)
+	a.True(math.IsInf(costs["e"].Cost, 1))
+	options.IsValid = func(cost float64) bool {
+		return !math.IsInf(cost, 1)
+	}
+	_, err = options.TryDijkstra("a", 0)
+	a.ErrorAs(err, &invalidErr)
+	a.Equal("e", invalidErr.To)
+}
+
+func TestDijkstraStats(t *testing.T) {
+	a := assert.New(t)
+	// b and d are both first reached at a higher cost, then updated in place.
+	options := WeightedOptions(map[string]map[string]int{
+		"s": {"a": 1, "c": 2},
+		"a": {"b": 5},
+		"c": {"b": 1, "d": 9},
+		"b": {"s": 1, "d": 1},
+	})
+	costs, stats := options.DijkstraStats("s", 0)
+	a.Equal(options.Dijkstra("s", 0), costs)
+	a.Equal(5, stats.Finalized)
+	a.Equal(5, stats.Pushes)
+	a.Equal(2, stats.Updates)
+	// Only the edge back to s is discarded.
+	a.Equal(1, stats.Stale)
+	a.Equal(2, stats.MaxQueueLen)
+}
+
+func TestSelfLoops(t *testing.T) {
+	a := assert.New(t)
+	graph := RandomCostGraph(rand.New(rand.NewSource(2)), 6, 6, 9)
+	options := MockOptions(graph)
+	start := Key{X: 0, Y: 0}
+	costs, stats := options.DijkstraStats(start, Cost(0))
+
+	looped := options
+	looped.Edges = func(from Key) []Key {
+		return append(options.Edges(from), from)
+	}
+	accumulator := looped.Accumulator
+	looped.Accumulator = func(agg Cost, from, to Key) (Cost, bool) {
+		a.NotEqual(from, to)
+		return accumulator(agg, from, to)
+	}
+	looped.OnRelax = func(from, to Key, _ Cost, _ bool) {
+		a.NotEqual(from, to)
+	}
+	loopedCosts, loopedStats := looped.DijkstraStats(start, Cost(0))
+	a.Equal(costs, loopedCosts)
+	a.Equal(stats, loopedStats)
+
+	edges := func(from string) iter.Seq[dijkstra.Edge[string, int]] {
+		return func(yield func(dijkstra.Edge[string, int]) bool) {
+			if !yield(dijkstra.Edge[string, int]{To: from, Weight: 0}) || from != "a" {
+				return
+			}
+			yield(dijkstra.Edge[string, int]{To: "b", Weight: 1})
+		}
+	}
+	streamed := dijkstra.DijkstraFromEdgeStream("a", 0, dijkstra.Ascending[int](), func(agg, weight int) int { return agg + weight }, edges)
+	a.Equal(1, streamed["b"].Cost)
+	a.Len(streamed, 2)
+}
+
 func Costs2Graph(costs map[Key]dijkstra.Node[Key, Cost]) map[Key]Cost {
 	graph := make(map[Key]Cost)
 	for node, cost := range costs {
@@ -200,3 +1383,26 @@ func Text2Graph(text string) map[Key]Cost {
 	}
 	return graph
 }
+
+func TestSizeHint(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(20, 20, 1))
+	want := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	for _, hint := range []int{1, 400, 100000} {
+		options.SizeHint = hint
+		a.Equal(want, options.Dijkstra(Key{X: 0, Y: 0}, Cost(0)), "hint %d", hint)
+	}
+}
+
+func BenchmarkSizeHint(b *testing.B) {
+	options := MockOptions(FlatGraph(400, 400, 1))
+	for _, hint := range []int{0, 400 * 400} {
+		options.SizeHint = hint
+		b.Run(fmt.Sprint(hint), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+			}
+		})
+	}
+}
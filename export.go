@@ -0,0 +1,175 @@
+package dijkstra
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChildrenTree inverts the Prev of the costs into the children of each node in the shortest-path tree,
+// e.g. to list every node reached through a node.
+// The roots are the nodes whose Prev is nil, and the nodes without children have no entry.
+// The children of a node are in no particular order.
+func ChildrenTree[K comparable, C any](costs map[K]Node[K, C]) map[K][]K {
+	children := make(map[K][]K)
+	for key, node := range costs {
+		if node.Prev != nil {
+			children[*node.Prev] = append(children[*node.Prev], key)
+		}
+	}
+	return children
+}
+
+// treeJSONNode is a node of the shortest-path tree encoded by TreeJSON.
+type treeJSONNode[C any] struct {
+	ID       string             `json:"id"`
+	Cost     C                  `json:"cost"`
+	Children []*treeJSONNode[C] `json:"children"`
+}
+
+// TreeJSON encodes the shortest-path tree rooted at root as nested JSON objects
+// of the form {"id": ..., "cost": ..., "children": [...]}, e.g. for D3 tree renderers.
+// keyStr : Function to convert a key to its id. Defaults to fmt.Sprint.
+// Children are ordered by id.
+func TreeJSON[K comparable, C any](costs map[K]Node[K, C], root K, keyStr func(K) string) ([]byte, error) {
+	if keyStr == nil {
+		keyStr = func(key K) string { return fmt.Sprint(key) }
+	}
+	if _, ok := costs[root]; !ok {
+		return nil, fmt.Errorf("the root node is not in the costs: %v", root)
+	}
+	children := ChildrenTree(costs)
+	visited := make(map[K]struct{})
+	var build func(key K) (*treeJSONNode[C], error)
+	build = func(key K) (*treeJSONNode[C], error) {
+		if _, ok := visited[key]; ok {
+			return nil, fmt.Errorf("the shortest-path tree has a cycle at node: %v", key)
+		}
+		visited[key] = struct{}{}
+		node := &treeJSONNode[C]{ID: keyStr(key), Cost: costs[key].Cost, Children: []*treeJSONNode[C]{}}
+		for _, child := range children[key] {
+			c, err := build(child)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, c)
+		}
+		sort.Slice(node.Children, func(i, j int) bool {
+			return node.Children[i].ID < node.Children[j].ID
+		})
+		return node, nil
+	}
+	tree, err := build(root)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(tree)
+}
+
+// costRecord is a node of the costs encoded by MarshalCosts.
+type costRecord[K comparable, C any] struct {
+	Key       K    `json:"key"`
+	Cost      C    `json:"cost"`
+	Prev      *K   `json:"prev"`
+	Prevs     []K  `json:"prevs,omitempty"`
+	Finalized bool `json:"finalized,omitempty"`
+}
+
+// MarshalCosts encodes the costs as a JSON array of {"key": ..., "cost": ..., "prev": ...} records,
+// with "prev" null for the start nodes, so that keys of any type can be encoded.
+// "prevs" and "finalized" are left out when empty.
+// The records are ordered by their encoded key. Node.Data is not encoded.
+func MarshalCosts[K comparable, C any](costs map[K]Node[K, C]) ([]byte, error) {
+	type keyed struct {
+		key    string
+		record costRecord[K, C]
+	}
+	records := make([]keyed, 0, len(costs))
+	for key, node := range costs {
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, keyed{string(k), costRecord[K, C]{Key: key, Cost: node.Cost, Prev: node.Prev, Prevs: node.Prevs, Finalized: node.Finalized}})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].key < records[j].key
+	})
+	out := make([]costRecord[K, C], len(records))
+	for i, r := range records {
+		out[i] = r.record
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalCosts decodes the costs encoded by MarshalCosts.
+func UnmarshalCosts[K comparable, C any](data []byte) (map[K]Node[K, C], error) {
+	var records []costRecord[K, C]
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	costs := make(map[K]Node[K, C], len(records))
+	for _, r := range records {
+		if _, ok := costs[r.Key]; ok {
+			return nil, fmt.Errorf("the costs have a duplicate node: %v", r.Key)
+		}
+		costs[r.Key] = Node[K, C]{Key: r.Key, Cost: r.Cost, Prev: r.Prev, Prevs: r.Prevs, Finalized: r.Finalized}
+	}
+	return costs, nil
+}
+
+// ExportDOT renders the shortest-path tree as a Graphviz digraph, with an edge from each node
+// to the nodes it is the Prev of, labeled with the cost added by the edge when the costs are numbers,
+// and with the cost of the node reached otherwise.
+// The start nodes, which have no Prev, are drawn with a double border.
+// format : Function to convert a key to its id. Defaults to fmt.Sprint.
+// Nodes and edges are ordered by id.
+func ExportDOT[K comparable, C any](costs map[K]Node[K, C], format func(K) string) string {
+	if format == nil {
+		format = func(key K) string { return fmt.Sprint(key) }
+	}
+	var nodes, edges []string
+	for key, node := range costs {
+		id := strconv.Quote(format(key))
+		if node.Prev == nil {
+			nodes = append(nodes, fmt.Sprintf("\t%s [peripheries=2];\n", id))
+			continue
+		}
+		nodes = append(nodes, fmt.Sprintf("\t%s;\n", id))
+		var prevCost C
+		if prev, ok := costs[*node.Prev]; ok {
+			prevCost = prev.Cost
+		}
+		edges = append(edges, fmt.Sprintf("\t%s -> %s [label=%q];\n", strconv.Quote(format(*node.Prev)), id, edgeLabel(prevCost, node.Cost)))
+	}
+	sort.Strings(nodes)
+	sort.Strings(edges)
+	var builder strings.Builder
+	builder.WriteString("digraph {\n")
+	for _, line := range nodes {
+		builder.WriteString(line)
+	}
+	for _, line := range edges {
+		builder.WriteString(line)
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// edgeLabel formats the cost added from prev to next if the costs are numbers, or next otherwise.
+func edgeLabel[C any](prev, next C) string {
+	p, n := reflect.ValueOf(prev), reflect.ValueOf(next)
+	switch {
+	case n.CanInt():
+		return strconv.FormatInt(n.Int()-p.Int(), 10)
+	case n.CanUint():
+		return strconv.FormatUint(n.Uint()-p.Uint(), 10)
+	case n.CanFloat():
+		return strconv.FormatFloat(n.Float()-p.Float(), 'g', -1, n.Type().Bits())
+	default:
+		return fmt.Sprint(next)
+	}
+}
@@ -0,0 +1,143 @@
+package dijkstra_test
+
+import (
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeJSON(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  1 
+	■  1 
+	`)
+	options := MockOptions(graph)
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	data, err := dijkstra.TreeJSON(costs, Key{X: 0, Y: 0}, nil)
+	a.NoError(err)
+	a.JSONEq(`{"id": "(0, 0)", "cost": 0, "children": [
+		{"id": "(1, 0)", "cost": 1, "children": [
+			{"id": "(1, 1)", "cost": 2, "children": []}
+		]}
+	]}`, string(data))
+}
+
+func TestChildrenTree(t *testing.T) {
+	a := assert.New(t)
+	graph := RandomWallGraph(12, 12)
+	options := MockOptions(graph)
+	starts := []Key{{X: 0, Y: 0}, {X: 11, Y: 11}}
+	for _, start := range starts {
+		graph[start] = 1
+	}
+	costs := options.DijkstraMulti(starts, Cost(0))
+	children := dijkstra.ChildrenTree(costs)
+	for parent, kids := range children {
+		for _, child := range kids {
+			a.Equal(parent, *costs[child].Prev)
+		}
+	}
+	var size func(key Key) int
+	size = func(key Key) int {
+		n := 1
+		for _, child := range children[key] {
+			n += size(child)
+		}
+		return n
+	}
+	total := 0
+	for key, node := range costs {
+		if node.Prev == nil {
+			total += size(key)
+		}
+	}
+	a.Equal(len(costs), total)
+}
+
+func TestTreeJSONCycle(t *testing.T) {
+	a := assert.New(t)
+	x, y := "x", "y"
+	costs := map[string]dijkstra.Node[string, int]{
+		x: {Key: x, Cost: 1, Prev: &y},
+		y: {Key: y, Cost: 2, Prev: &x},
+	}
+	_, err := dijkstra.TreeJSON(costs, x, nil)
+	a.Error(err)
+}
+
+func TestMarshalCosts(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  1  1 
+	■  ■  1 
+	1  1  1 
+	`)
+	options := MockOptions(graph)
+	start, goal := Key{X: 0, Y: 0}, Key{X: 2, Y: 0}
+	costs := options.Dijkstra(start, Cost(0))
+	data, err := dijkstra.MarshalCosts(costs)
+	a.NoError(err)
+	decoded, err := dijkstra.UnmarshalCosts[Key, Cost](data)
+	a.NoError(err)
+	a.Equal(costs, decoded)
+	want, wantErr := options.PathResolve(costs, goal)
+	got, gotErr := options.PathResolve(decoded, goal)
+	a.Equal(want, got)
+	a.Equal(wantErr, gotErr)
+
+	data, err = dijkstra.MarshalCosts(options.Dijkstra(start, Cost(0)))
+	a.NoError(err)
+	again, err := dijkstra.MarshalCosts(decoded)
+	a.NoError(err)
+	a.Equal(data, again)
+}
+
+func TestUnmarshalCostsDuplicate(t *testing.T) {
+	_, err := dijkstra.UnmarshalCosts[string, int]([]byte(`[
+		{"key": "x", "cost": 0, "prev": null},
+		{"key": "x", "cost": 1, "prev": "x"}
+	]`))
+	assert.Error(t, err)
+}
+
+func TestExportDOT(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  3 
+	■  1 
+	`)
+	options := MockOptions(graph)
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	a.Equal(`digraph {
+	"(0, 0)" [peripheries=2];
+	"(1, 0)";
+	"(1, 1)";
+	"(0, 0)" -> "(1, 0)" [label="3"];
+	"(1, 0)" -> "(1, 1)" [label="1"];
+}
+`, dijkstra.ExportDOT(costs, Key.String))
+}
+
+func TestExportDOTAnyCost(t *testing.T) {
+	a := assert.New(t)
+	type hops struct{ Count int }
+	s := "s"
+	costs := map[string]dijkstra.Node[string, hops]{
+		"s": {Key: "s", Cost: hops{0}},
+		"t": {Key: "t", Cost: hops{1}, Prev: &s},
+	}
+	a.Equal(`digraph {
+	"s" [peripheries=2];
+	"t";
+	"s" -> "t" [label="{1}"];
+}
+`, dijkstra.ExportDOT(costs, nil))
+
+	float := map[string]dijkstra.Node[string, float32]{
+		"s": {Key: "s", Cost: 0.5},
+		"t": {Key: "t", Cost: 0.75, Prev: &s},
+	}
+	a.Contains(dijkstra.ExportDOT(float, nil), `"s" -> "t" [label="0.25"];`)
+}
@@ -1,6 +1,6 @@
 module github.com/naycoma/dijkstra
 
-go 1.21.3
+go 1.24
 
 require (
 	github.com/samber/lo v1.39.0
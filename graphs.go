@@ -0,0 +1,82 @@
+package dijkstra
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+)
+
+// StringGraphOptions creates options for a graph given as from -> to -> weight,
+// such as a graph decoded from JSON.
+// Costs are the sums of the weights, and nodes missing from adj have no edges.
+func StringGraphOptions(adj map[string]map[string]float64) Options[string, float64] {
+	return Options[string, float64]{
+		Accumulator: func(agg float64, from, to string) (float64, bool) {
+			weight, ok := adj[from][to]
+			return agg + weight, ok
+		},
+		Less: Ascending[float64](),
+		Edges: func(from string) []string {
+			dest := make([]string, 0, len(adj[from]))
+			for to := range adj[from] {
+				dest = append(dest, to)
+			}
+			sort.Strings(dest)
+			return dest
+		},
+	}
+}
+
+// Ascending creates the comparator ordering costs from the lowest, for Options.Less.
+func Ascending[C cmp.Ordered]() func(i, j C) bool {
+	return func(i, j C) bool {
+		return i < j
+	}
+}
+
+// OrderedOptions creates options for costs of an ordered type, ordered from the lowest.
+// Edges is left unset, so it is derived from the key type if it implements Adjacent() []K,
+// and can otherwise be set on the returned options.
+func OrderedOptions[K comparable, C cmp.Ordered](accumulator func(agg C, from, to K) (C, bool)) Options[K, C] {
+	return Options[K, C]{
+		Accumulator: accumulator,
+		Less:        Ascending[C](),
+	}
+}
+
+// FromAdjacency creates options for a graph given as adjacency lists of weighted edges.
+// Costs are the accumulated costs plus the weights with add, the lightest of parallel edges being used,
+// and nodes missing from adj have no edges.
+// The edges of a node are listed in the order of adj.
+// It takes no initial cost, as the options hold none: pass it to the search, e.g. Dijkstra(start, initial).
+func FromAdjacency[K comparable, C any](adj map[K][]Edge[K, C], less func(i, j C) bool, add func(agg, weight C) C) Options[K, C] {
+	weights := make(map[K]map[K]C, len(adj))
+	dests := make(map[K][]K, len(adj))
+	for from, edges := range adj {
+		lightest := make(map[K]C, len(edges))
+		for _, edge := range edges {
+			weight, ok := lightest[edge.To]
+			if !ok {
+				dests[from] = append(dests[from], edge.To)
+			}
+			if !ok || less(edge.Weight, weight) {
+				lightest[edge.To] = edge.Weight
+			}
+		}
+		weights[from] = lightest
+	}
+	return Options[K, C]{
+		Accumulator: func(agg C, from, to K) (C, bool) {
+			weight, ok := weights[from][to]
+			if !ok {
+				return agg, false
+			}
+			return add(agg, weight), true
+		},
+		Less: less,
+		Add:  add,
+		Edges: func(from K) []K {
+			return slices.Clone(dests[from])
+		},
+	}
+}
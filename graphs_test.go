@@ -0,0 +1,76 @@
+package dijkstra_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleStringGraphOptions() {
+	data, err := os.ReadFile("testdata/graph.json")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var adj map[string]map[string]float64
+	if err := json.Unmarshal(data, &adj); err != nil {
+		fmt.Println(err)
+		return
+	}
+	options := dijkstra.StringGraphOptions(adj)
+	costs := options.Dijkstra("home", 0)
+	path, err := options.ShortestPath(costs, "office")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(path, costs["office"].Cost)
+	// Output: [home station park office] 3.5
+}
+
+func ExampleOrderedOptions() {
+	options := dijkstra.OrderedOptions(func(agg int, from, to AdjacentKey) (int, bool) {
+		return agg + int(to), true
+	})
+	costs := options.Dijkstra(0, 0)
+	fmt.Println(costs[3].Cost)
+	// Output: 6
+}
+
+func ExampleFromAdjacency() {
+	type Edge = dijkstra.Edge[string, int]
+	options := dijkstra.FromAdjacency(map[string][]Edge{
+		"home":    {{To: "station", Weight: 5}, {To: "park", Weight: 2}},
+		"park":    {{To: "station", Weight: 1}},
+		"station": {{To: "office", Weight: 10}},
+	}, dijkstra.Ascending[int](), func(agg, weight int) int { return agg + weight })
+	costs := options.Dijkstra("home", 0)
+	path, err := options.ShortestPath(costs, "office")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(path, costs["office"].Cost)
+	// Output: [home park station office] 13
+}
+
+func TestFromAdjacency(t *testing.T) {
+	a := assert.New(t)
+	type Edge = dijkstra.Edge[int, int]
+	options := dijkstra.FromAdjacency(map[int][]Edge{
+		1: {{To: 2, Weight: 4}, {To: 2, Weight: 1}, {To: 3, Weight: 9}},
+		// 3 has no entry, and 4 is only reached from 2.
+		2: {{To: 3, Weight: 1}, {To: 4, Weight: 1}},
+	}, dijkstra.Ascending[int](), func(agg, weight int) int { return agg + weight })
+	a.Equal([]int{2, 3}, options.Edges(1))
+	a.Empty(options.Edges(3))
+	costs := options.Dijkstra(1, 0)
+	a.Len(costs, 4)
+	a.Equal(1, costs[2].Cost)
+	a.Equal(2, costs[3].Cost)
+	a.Equal(2, costs[4].Cost)
+}
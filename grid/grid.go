@@ -0,0 +1,59 @@
+// Package grid creates options for searching 2D grids.
+package grid
+
+import (
+	"fmt"
+
+	"github.com/naycoma/dijkstra"
+)
+
+// Point is a cell of a grid.
+type Point struct {
+	X int
+	Y int
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%d, %d)", p.X, p.Y)
+}
+
+var (
+	directions4 = []Point{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}}
+	directions8 = []Point{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}, {X: 1, Y: 1}, {X: 1, Y: -1}, {X: -1, Y: 1}, {X: -1, Y: -1}}
+)
+
+// Options4 creates options for a grid whose cells are connected to their 4 orthogonal neighbors.
+// passable : Function to report whether a cell can be entered, which also bounds the grid.
+// weight : Function to retrieve the cost of entering a cell.
+func Options4[C dijkstra.Number](passable func(x, y int) bool, weight func(x, y int) C) dijkstra.Options[Point, C] {
+	return options(directions4, passable, weight)
+}
+
+// Options8 creates options for a grid whose cells are also connected to their 4 diagonal neighbors.
+// Entering a cell diagonally costs the same as entering it orthogonally.
+// passable : Function to report whether a cell can be entered, which also bounds the grid.
+// weight : Function to retrieve the cost of entering a cell.
+func Options8[C dijkstra.Number](passable func(x, y int) bool, weight func(x, y int) C) dijkstra.Options[Point, C] {
+	return options(directions8, passable, weight)
+}
+
+func options[C dijkstra.Number](directions []Point, passable func(x, y int) bool, weight func(x, y int) C) dijkstra.Options[Point, C] {
+	return dijkstra.Options[Point, C]{
+		Accumulator: func(agg C, from, to Point) (C, bool) {
+			if !passable(to.X, to.Y) {
+				return agg, false
+			}
+			return agg + weight(to.X, to.Y), true
+		},
+		Less: dijkstra.Ascending[C](),
+		Edges: func(p Point) []Point {
+			edges := make([]Point, 0, len(directions))
+			for _, d := range directions {
+				if to := (Point{X: p.X + d.X, Y: p.Y + d.Y}); passable(to.X, to.Y) {
+					edges = append(edges, to)
+				}
+			}
+			return edges
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package grid_test
+
+import (
+	"testing"
+
+	"github.com/naycoma/dijkstra/grid"
+	"github.com/stretchr/testify/assert"
+)
+
+// walls is a 4x3 grid with walls marked 0.
+var walls = [][]int{
+	{1, 1, 1, 1},
+	{0, 0, 1, 0},
+	{1, 1, 1, 1},
+}
+
+func passable(x, y int) bool {
+	return y >= 0 && y < len(walls) && x >= 0 && x < len(walls[y]) && walls[y][x] != 0
+}
+
+func weight(x, y int) int {
+	return walls[y][x]
+}
+
+func TestOptions4(t *testing.T) {
+	a := assert.New(t)
+	options := grid.Options4(passable, weight)
+	start, goal := grid.Point{X: 0, Y: 0}, grid.Point{X: 0, Y: 2}
+	costs := options.Dijkstra(start, 0)
+	a.Len(costs, 9)
+	a.Equal(6, costs[goal].Cost)
+	path, err := options.PathResolve(costs, goal)
+	a.NoError(err)
+	a.Equal([]grid.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 2}, {X: 0, Y: 2}}, path)
+}
+
+func TestOptions8(t *testing.T) {
+	a := assert.New(t)
+	options := grid.Options8(passable, weight)
+	start, goal := grid.Point{X: 0, Y: 0}, grid.Point{X: 0, Y: 2}
+	costs := options.Dijkstra(start, 0)
+	a.Len(costs, 9)
+	a.Equal(4, costs[goal].Cost)
+	path, err := options.PathResolve(costs, goal)
+	a.NoError(err)
+	a.Len(path, 5)
+	a.Equal(grid.Point{X: 2, Y: 1}, path[2])
+}
@@ -0,0 +1,54 @@
+package dijkstra
+
+import "fmt"
+
+// Lexicographic is a cost of two objectives, ordered by First, then by Second to break its ties,
+// such as the distance then the number of turns.
+// It prints as (First, Second) and is encoded to JSON as {"first": ..., "second": ...}.
+type Lexicographic[A, B any] struct {
+	First  A `json:"first"`
+	Second B `json:"second"`
+}
+
+func (l Lexicographic[A, B]) String() string {
+	return fmt.Sprintf("(%v, %v)", l.First, l.Second)
+}
+
+// LexLess creates the comparator of Lexicographic costs from the comparators of their objectives, for Options.Less.
+func LexLess[A, B any](lessA func(i, j A) bool, lessB func(i, j B) bool) func(i, j Lexicographic[A, B]) bool {
+	return func(i, j Lexicographic[A, B]) bool {
+		if lessA(i.First, j.First) {
+			return true
+		}
+		if lessA(j.First, i.First) {
+			return false
+		}
+		return lessB(i.Second, j.Second)
+	}
+}
+
+// LexAdd creates the function adding Lexicographic costs objective by objective, for Options.Add.
+func LexAdd[A, B any](addA func(a, b A) A, addB func(a, b B) B) func(a, b Lexicographic[A, B]) Lexicographic[A, B] {
+	return func(a, b Lexicographic[A, B]) Lexicographic[A, B] {
+		return Lexicographic[A, B]{First: addA(a.First, b.First), Second: addB(a.Second, b.Second)}
+	}
+}
+
+// LexAccumulator combines the accumulators of two objectives into the accumulator of their Lexicographic cost.
+// An edge is passable when it is passable for both objectives.
+func LexAccumulator[K comparable, A, B any](
+	first func(agg A, from, to K) (A, bool),
+	second func(agg B, from, to K) (B, bool),
+) func(agg Lexicographic[A, B], from, to K) (Lexicographic[A, B], bool) {
+	return func(agg Lexicographic[A, B], from, to K) (Lexicographic[A, B], bool) {
+		a, ok := first(agg.First, from, to)
+		if !ok {
+			return agg, false
+		}
+		b, ok := second(agg.Second, from, to)
+		if !ok {
+			return agg, false
+		}
+		return Lexicographic[A, B]{First: a, Second: b}, true
+	}
+}
@@ -0,0 +1,73 @@
+package dijkstra_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/stretchr/testify/assert"
+)
+
+type Lex = dijkstra.Lexicographic[Cost, int]
+
+func TestLexicographic(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  1  1 
+	1  ■  1 
+	1  1  1 
+	`)
+	distance := MockOptions(graph)
+	// Distance first, then the number of steps.
+	options := dijkstra.Options[Key, Lex]{
+		Accumulator: dijkstra.LexAccumulator(distance.Accumulator, func(agg int, from, to Key) (int, bool) {
+			return agg + 1, true
+		}),
+		Less:  dijkstra.LexLess(distance.Less, dijkstra.Ascending[int]()),
+		Edges: distance.Edges,
+	}
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Lex{})
+	a.Equal(Lex{First: 4, Second: 4}, costs[Key{X: 2, Y: 2}].Cost)
+
+	less := options.Less
+	a.True(less(Lex{First: 1, Second: 9}, Lex{First: 2, Second: 0}))
+	a.True(less(Lex{First: 1, Second: 0}, Lex{First: 1, Second: 1}))
+	a.False(less(Lex{First: 1, Second: 1}, Lex{First: 1, Second: 1}))
+	add := dijkstra.LexAdd(func(a, b Cost) Cost { return a + b }, func(a, b int) int { return a + b })
+	a.Equal(Lex{First: 3, Second: 5}, add(Lex{First: 1, Second: 2}, Lex{First: 2, Second: 3}))
+
+	a.Equal("(4, 4)", fmt.Sprint(costs[Key{X: 2, Y: 2}].Cost))
+	data, err := json.Marshal(Lex{First: 4, Second: 1})
+	a.NoError(err)
+	a.JSONEq(`{"first": 4, "second": 1}`, string(data))
+}
+
+func TestLexicographicTurns(t *testing.T) {
+	a := assert.New(t)
+	graph := FlatGraph(4, 4, 1)
+	distance := MockOptions(graph)
+	// The state is the direction of the last move, none at the start.
+	options := dijkstra.WithState(distance.Edges, func(agg Lex, from Key, dir Key, to Key) (Key, Lex, bool) {
+		cost, ok := distance.Accumulator(agg.First, from, to)
+		next := Key{X: to.X - from.X, Y: to.Y - from.Y}
+		turns := agg.Second
+		if dir != (Key{}) && dir != next {
+			turns++
+		}
+		return next, Lex{First: cost, Second: turns}, ok
+	}, dijkstra.LexLess(distance.Less, dijkstra.Ascending[int]()))
+	start, goal := Key{X: 0, Y: 0}, Key{X: 3, Y: 3}
+	costs := options.Dijkstra(dijkstra.StateKey[Key, Key]{Key: start}, Lex{})
+	path, err := dijkstra.StatePath(options, costs, goal)
+	a.NoError(err)
+	a.Len(path, 7)
+	a.Equal(Cost(6), PathCost(graph, path))
+	turns := 0
+	for i := 2; i < len(path); i++ {
+		if path[i].X-path[i-1].X != path[i-1].X-path[i-2].X {
+			turns++
+		}
+	}
+	a.Equal(1, turns)
+}
@@ -0,0 +1,56 @@
+package dijkstra
+
+// PathBottleneck finds the most expensive single edge along the path.
+// weight : Function to retrieve the weight of the edge between two consecutive nodes.
+// less : Comparison function to determine the order of weights.
+// returns : The edge with the maximum weight, or false if the path has fewer than two nodes
+// or one of its edges has no weight.
+func PathBottleneck[K comparable, C any](
+	path []K,
+	weight func(from, to K) (C, bool),
+	less func(i C, j C) bool,
+) (from, to K, w C, ok bool) {
+	for i := 1; i < len(path); i++ {
+		current, has := weight(path[i-1], path[i])
+		if !has {
+			var zero K
+			var none C
+			return zero, zero, none, false
+		}
+		if !ok || less(w, current) {
+			from, to, w, ok = path[i-1], path[i], current, true
+		}
+	}
+	return from, to, w, ok
+}
+
+// PathCost returns the total cost to reach the goal node.
+// returns : The cost, or NotReachableError if the goal is not reachable.
+func (c Options[K, C]) PathCost(costs map[K]Node[K, C], goal K) (C, error) {
+	goal = c.canonical(goal)
+	node, ok := costs[goal]
+	if !ok {
+		var cost C
+		return cost, newNotReachableError(costs, c.Less, goal)
+	}
+	return node.Cost, nil
+}
+
+// PathWithCosts resolves the path from the start node to the goal node
+// with the cost accumulated up to each of its nodes.
+func (c Options[K, C]) PathWithCosts(costs map[K]Node[K, C], goal K) ([]Node[K, C], error) {
+	path, err := c.ShortestPath(costs, goal)
+	if err != nil {
+		return nil, err
+	}
+	return nodesOf(costs, path), nil
+}
+
+// nodesOf looks up the nodes of the path in the costs.
+func nodesOf[K comparable, C any](costs map[K]Node[K, C], path []K) []Node[K, C] {
+	nodes := make([]Node[K, C], len(path))
+	for i, key := range path {
+		nodes[i] = costs[key]
+	}
+	return nodes
+}
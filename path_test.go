@@ -0,0 +1,62 @@
+package dijkstra_test
+
+import (
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathBottleneck(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  3  1 
+	■  ■  1 
+	1  1  2 
+	`)
+	weight := func(from, to Key) (Cost, bool) {
+		cost, ok := graph[to]
+		return cost, ok
+	}
+	less := func(i, j Cost) bool { return i < j }
+	options := MockOptions(graph)
+	path, err := options.ShortestPath(options.Dijkstra(Key{X: 0, Y: 0}, Cost(0)), Key{X: 2, Y: 0})
+	a.NoError(err)
+	from, to, w, ok := dijkstra.PathBottleneck(path, weight, less)
+	a.True(ok)
+	a.Equal(Key{X: 0, Y: 0}, from)
+	a.Equal(Key{X: 0, Y: 1}, to)
+	a.Equal(Cost(3), w)
+
+	_, _, _, ok = dijkstra.PathBottleneck([]Key{{X: 0, Y: 0}}, weight, less)
+	a.False(ok)
+	_, _, _, ok = dijkstra.PathBottleneck([]Key{{X: 0, Y: 0}, {X: 1, Y: 0}}, weight, less)
+	a.False(ok)
+}
+
+func TestPathCost(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  2  3 
+	■  ■  1 
+	`)
+	options := MockOptions(graph)
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	cost, err := options.PathCost(costs, Key{X: 1, Y: 2})
+	a.NoError(err)
+	a.Equal(Cost(6), cost)
+
+	nodes, err := options.PathWithCosts(costs, Key{X: 1, Y: 2})
+	a.NoError(err)
+	a.Equal([]Cost{0, 2, 5, 6}, lo.Map(nodes, func(node dijkstra.Node[Key, Cost], _ int) Cost {
+		return node.Cost
+	}))
+	a.Nil(nodes[0].Prev)
+
+	var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+	_, err = options.PathCost(costs, Key{X: 1, Y: 0})
+	a.ErrorAs(err, &notReachableErr)
+	_, err = options.PathWithCosts(costs, Key{X: 1, Y: 0})
+	a.ErrorAs(err, &notReachableErr)
+}
@@ -0,0 +1,201 @@
+package dijkstra
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Reachable finds the nodes reachable from the start node, including it, without computing their costs or paths.
+// Unless CostGated is set, it traverses the graph breadth-first, assuming that whether Accumulator
+// accepts an edge does not depend on the accumulated cost, which it is given as the zero value.
+// With CostGated, MaxNodes, StrictMonotonic, RejectNaN or IsValid, and for the options of WithState,
+// the costs decide what is reached, so it runs Dijkstra from the zero cost and returns the keys of the costs instead.
+func (c Options[K, C]) Reachable(start K) map[K]struct{} {
+	c = c.prepare()
+	start = c.canonical(start)
+	var zero C
+	if c.CostGated || c.edgesAt != nil || c.MaxNodes > 0 || c.StrictMonotonic || c.RejectNaN || c.IsValid != nil {
+		costs := c.run(query[K, C]{starts: []K{start}, initial: zero})
+		reachable := make(map[K]struct{}, len(costs))
+		for key := range costs {
+			reachable[key] = struct{}{}
+		}
+		return reachable
+	}
+	c.begin()
+	reachable := make(map[K]struct{}, c.SizeHint)
+	if (c.Blocked != nil && c.Blocked(start)) || (c.StartValid != nil && !c.StartValid(start)) {
+		return reachable
+	}
+	ctx := context.Background()
+	reachable[start] = struct{}{}
+	for queue := []K{start}; len(queue) > 0; queue = queue[1:] {
+		from := queue[0]
+		visit := func(to K) {
+			if _, ok := reachable[to]; ok || (c.Blocked != nil && c.Blocked(to)) {
+				return
+			}
+			reachable[to] = struct{}{}
+			queue = append(queue, to)
+		}
+		if c.WeightedEdges != nil {
+			for _, edge := range c.WeightedEdges(from) {
+				visit(c.canonical(edge.To))
+			}
+			continue
+		}
+		dests, err := c.edges(ctx, from, zero)
+		if err != nil {
+			panic(err)
+		}
+		for _, to := range dests {
+			if _, ok := reachable[to]; ok {
+				continue
+			}
+			_, ok, err := c.accumulate(ctx, zero, from, to)
+			if err != nil {
+				panic(err)
+			}
+			if ok {
+				visit(to)
+			}
+		}
+	}
+	return reachable
+}
+
+// Farthest finds the reachable node with the maximum cost from the start node.
+// The start node itself is not considered.
+// returns : The farthest node and its cost, or false if only the start node is reachable.
+func (c Options[K, C]) Farthest(start K, initial C) (farthest K, cost C, ok bool) {
+	start = c.canonical(start)
+	costs := c.Dijkstra(start, initial)
+	farthest, ok = maxCostNode(costs, c.Less, c.Tiebreak, func(node K) bool {
+		return node != start
+	})
+	if !ok {
+		return farthest, cost, false
+	}
+	return farthest, costs[farthest].Cost, true
+}
+
+// maxCostNode finds the node with the maximum cost, preferring the lowest key by tiebreak among equal costs.
+func maxCostNode[K comparable, C any](costs map[K]Node[K, C], less func(i C, j C) bool, tiebreak func(i, j K) bool, include func(node K) bool) (max K, ok bool) {
+	var maxCost C
+	for key, node := range costs {
+		if !include(key) {
+			continue
+		}
+		if !ok || less(maxCost, node.Cost) ||
+			(tiebreak != nil && !less(node.Cost, maxCost) && tiebreak(key, max)) {
+			max, maxCost, ok = key, node.Cost, true
+		}
+	}
+	return
+}
+
+// SubsetDistances computes the costs between every pair of nodes in the subset,
+// running one search per member and dropping every node outside the subset.
+// Pairs that are not reachable are absent from the inner maps.
+func (c Options[K, C]) SubsetDistances(subset []K, initial C) map[K]map[K]C {
+	subset = c.canonicalKeys(subset)
+	distances := make(map[K]map[K]C, len(subset))
+	for _, from := range subset {
+		costs := c.Dijkstra(from, initial)
+		inner := make(map[K]C)
+		for _, to := range subset {
+			if node, ok := costs[to]; ok {
+				inner[to] = node.Cost
+			}
+		}
+		distances[from] = inner
+	}
+	return distances
+}
+
+// AllPairs runs one search from each of the nodes, keyed by their start node,
+// each inner map being what Dijkstra returns for that start.
+// It runs len(nodes) full searches, so it suits modest graphs.
+func (c Options[K, C]) AllPairs(nodes []K, initial C) map[K]map[K]Node[K, C] {
+	c = c.prepare()
+	nodes = c.canonicalKeys(nodes)
+	all := make(map[K]map[K]Node[K, C], len(nodes))
+	for _, start := range nodes {
+		all[start] = c.search(start, initial)
+	}
+	return all
+}
+
+// AllPairsParallel runs the searches of AllPairs on a pool of workers, runtime.NumCPU() of them if workers is not positive.
+// The functions of the options are called concurrently, so they must be safe for concurrent use.
+func (c Options[K, C]) AllPairsParallel(nodes []K, initial C, workers int) map[K]map[K]Node[K, C] {
+	c = c.prepare()
+	nodes = c.canonicalKeys(nodes)
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	results := make([]map[K]Node[K, C], len(nodes))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(nodes)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = c.search(nodes[i], initial)
+			}
+		}()
+	}
+	for i := range nodes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	all := make(map[K]map[K]Node[K, C], len(nodes))
+	for i, start := range nodes {
+		all[start] = results[i]
+	}
+	return all
+}
+
+// ShortestPathBetween finds the path from the start node to the goal node and its cost,
+// stopping the search as soon as the goal is settled.
+// returns : The path and its cost, or NotReachableError if the goal is not reachable.
+func (c Options[K, C]) ShortestPathBetween(start, goal K, initial C) ([]K, C, error) {
+	goal = c.canonical(goal)
+	costs := c.DijkstraTo(start, goal, initial)
+	path, err := c.ShortestPath(costs, goal)
+	if err != nil {
+		var cost C
+		return nil, cost, err
+	}
+	return path, costs[goal].Cost, nil
+}
+
+// NearestGoal finds the goal with the minimum cost from the start node, and the path to it,
+// stopping the search as soon as the first of the goals is settled.
+// returns : The nearest goal, the path and its cost, or NotReachableError listing the goals if none is reachable.
+func (c Options[K, C]) NearestGoal(start K, initial C, goals []K) (goal K, path []K, cost C, err error) {
+	start, goals = c.canonical(start), c.canonicalKeys(goals)
+	targets := make(map[K]struct{}, len(goals))
+	for _, goal := range goals {
+		targets[goal] = struct{}{}
+	}
+	costs, found := c.DijkstraUntil(start, initial, func(key K, _ Node[K, C]) bool {
+		_, ok := targets[key]
+		return ok
+	})
+	if found == nil {
+		err := &NotReachableError[K, C]{Costs: costs, Start: start, Goals: goals}
+		if len(goals) > 0 {
+			err.Goal = goals[0]
+		}
+		return goal, nil, cost, err
+	}
+	path, err = c.ShortestPath(costs, *found)
+	if err != nil {
+		return goal, nil, cost, err
+	}
+	return *found, path, costs[*found].Cost, nil
+}
@@ -0,0 +1,152 @@
+package dijkstra_test
+
+import (
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFarthest(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  1  1  ■ 
+	■  ■  1  ■ 
+	1  1  1  1 
+	`)
+	options := MockOptions(graph)
+	farthest, cost, ok := options.Farthest(Key{X: 0, Y: 0}, Cost(0))
+	a.True(ok)
+	a.Equal(Key{X: 2, Y: 0}, farthest)
+	a.Equal(Cost(6), cost)
+}
+
+func TestFarthestTiebreak(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(3, 3, 1))
+	options.Tiebreak = func(i, j Key) bool {
+		return i.X < j.X || (i.X == j.X && i.Y < j.Y)
+	}
+	// The four corners are the farthest from the center.
+	for i := 0; i < 20; i++ {
+		farthest, cost, ok := options.Farthest(Key{X: 1, Y: 1}, Cost(0))
+		a.True(ok)
+		a.Equal(Cost(2), cost)
+		a.Equal(Key{X: 0, Y: 0}, farthest)
+	}
+}
+
+func TestFarthestIsolated(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  ■ 
+	■  1 
+	`)
+	options := MockOptions(graph)
+	_, _, ok := options.Farthest(Key{X: 0, Y: 0}, Cost(0))
+	a.False(ok)
+}
+
+func TestSubsetDistances(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  1  1  ■  1 
+	1  ■  1  ■  1 
+	1  1  1  ■  1 
+	`)
+	options := MockOptions(graph)
+	corner, center, opposite, island := Key{X: 0, Y: 0}, Key{X: 1, Y: 2}, Key{X: 2, Y: 0}, Key{X: 1, Y: 4}
+	distances := options.SubsetDistances([]Key{corner, center, opposite, island}, Cost(0))
+	a.Equal(map[Key]Cost{corner: 0, center: 3, opposite: 2}, distances[corner])
+	a.Equal(map[Key]Cost{corner: 3, center: 0, opposite: 3}, distances[center])
+	a.Equal(map[Key]Cost{island: 0}, distances[island])
+}
+
+func TestShortestPathBetween(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  ■  1  1  1 
+	1  1  1  ■  1 
+	■  ■  1  ■  1 
+	1  1  1  1  1 
+	`)
+	options := MockOptions(graph)
+	start, goal := Key{X: 0, Y: 0}, Key{X: 0, Y: 4}
+	path, cost, err := options.ShortestPathBetween(start, goal, Cost(0))
+	a.NoError(err)
+	a.Equal(options.Dijkstra(start, Cost(0))[goal].Cost, cost)
+	a.Equal(Cost(len(path)-1), cost)
+
+	_, _, err = options.ShortestPathBetween(start, Key{X: 2, Y: 0}, Cost(0))
+	var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+	a.ErrorAs(err, &notReachableErr)
+}
+
+func TestNearestGoal(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  ■  1  1  1 
+	1  1  1  ■  1 
+	■  ■  1  ■  1 
+	1  1  1  1  1 
+	`)
+	options := MockOptions(graph)
+	start := Key{X: 0, Y: 0}
+	goals := []Key{{X: 0, Y: 4}, {X: 3, Y: 0}, {X: 0, Y: 2}}
+	goal, path, cost, err := options.NearestGoal(start, Cost(0), goals)
+	a.NoError(err)
+	a.Equal(Key{X: 0, Y: 2}, goal)
+	a.Equal(Cost(4), cost)
+	a.Equal([]Key{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 0, Y: 2}}, path)
+
+	unreachable := []Key{{X: 2, Y: 0}, {X: 2, Y: 1}}
+	_, _, _, err = options.NearestGoal(start, Cost(0), unreachable)
+	var notReachableErr *dijkstra.NotReachableError[Key, Cost]
+	a.ErrorAs(err, &notReachableErr)
+	a.Equal(unreachable, notReachableErr.Goals)
+	a.Contains(err.Error(), "[(0, 2) (1, 2)]")
+}
+
+func TestAllPairs(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  ■  1  1 
+	1  1  1  ■ 
+	■  2  1  1 
+	`)
+	options := MockOptions(graph)
+	nodes := lo.Keys(graph)
+	all := options.AllPairs(nodes, Cost(0))
+	a.Len(all, len(nodes))
+	for _, start := range nodes {
+		a.Equal(options.Dijkstra(start, Cost(0)), all[start])
+	}
+	a.Equal(all, options.AllPairsParallel(nodes, Cost(0), 0))
+	a.Equal(all, options.AllPairsParallel(nodes, Cost(0), 3))
+}
+
+func TestOptionsReachable(t *testing.T) {
+	a := assert.New(t)
+	graph := RandomWallGraph(20, 20)
+	options := MockOptions(graph)
+	start := Key{X: 0, Y: 0}
+	graph[start] = 1
+	a.ElementsMatch(lo.Keys(options.Dijkstra(start, Cost(0))), lo.Keys(options.Reachable(start)))
+
+	// Within a budget of 3, only the nodes up to 3 steps away are reached.
+	budget := MockOptions(FlatGraph(5, 5, 1))
+	accumulator := budget.Accumulator
+	budget.Accumulator = func(agg Cost, from, to Key) (Cost, bool) {
+		next, ok := accumulator(agg, from, to)
+		return next, ok && next <= 3
+	}
+	budget.CostGated = true
+	a.Len(budget.Reachable(start), 10)
+
+	blocked := MockOptions(FlatGraph(3, 3, 1))
+	blocked.Blocked = func(node Key) bool {
+		return node.X == 1
+	}
+	a.ElementsMatch([]Key{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}}, lo.Keys(blocked.Reachable(start)))
+}
@@ -0,0 +1,144 @@
+package dijkstra
+
+// PriorityQueue is a priority queue of nodes, which Options.NewQueue can provide to replace the binary heap
+// of the searches, e.g. with a pairing heap or a bucket queue.
+type PriorityQueue[K comparable, C any] interface {
+	// Push queues the node ordered by priority.
+	Push(node Node[K, C], priority C)
+	// Pop removes and returns the queued node with the lowest priority.
+	Pop() Node[K, C]
+	// Empty reports whether no node is queued.
+	Empty() bool
+}
+
+// QueueUpdater is implemented by the PriorityQueues that can lower the priority of a queued node in place.
+// The searches queue the node again when a PriorityQueue does not implement it,
+// and skip the outdated node when it is popped.
+type QueueUpdater[K comparable, C any] interface {
+	// Update replaces the queued node with the same key, lowering its priority.
+	Update(node Node[K, C], priority C)
+}
+
+var _ PriorityQueue[int, int] = (*priorityNodes[int, int])(nil)
+
+// NewBucketQueue creates a bucket queue, holding one bucket per priority, for Options.NewQueue.
+// It orders the nodes by the value of their priorities rather than with less, in constant time,
+// but takes memory proportional to the highest priority, so it suits small integer costs.
+// Nodes queued at equal priorities are popped in any order.
+func NewBucketQueue[K comparable, C Unsigned](less func(i, j C) bool) PriorityQueue[K, C] {
+	return &bucketQueue[K, C]{}
+}
+
+type bucketQueue[K comparable, C Unsigned] struct {
+	buckets [][]Node[K, C]
+	// min is the lowest priority that may have a node queued.
+	min int
+	len int
+}
+
+func (q *bucketQueue[K, C]) Push(node Node[K, C], priority C) {
+	i := int(priority)
+	if i >= len(q.buckets) {
+		q.buckets = append(q.buckets, make([][]Node[K, C], i+1-len(q.buckets))...)
+	}
+	q.buckets[i] = append(q.buckets[i], node)
+	q.min = min(q.min, i)
+	q.len++
+}
+
+func (q *bucketQueue[K, C]) Pop() Node[K, C] {
+	for len(q.buckets[q.min]) == 0 {
+		q.min++
+	}
+	bucket := q.buckets[q.min]
+	node := bucket[len(bucket)-1]
+	bucket[len(bucket)-1] = Node[K, C]{}
+	q.buckets[q.min] = bucket[:len(bucket)-1]
+	q.len--
+	return node
+}
+
+func (q *bucketQueue[K, C]) Empty() bool {
+	return q.len == 0
+}
+
+// openSet is the queue of the entries of a search.
+type openSet[K comparable, C any] interface {
+	push(entry *heapNode[K, C])
+	// pop removes the entry with the lowest priority, or returns nil if the popped node was outdated.
+	pop() *heapNode[K, C]
+	// fix restores the order after the priority of a queued entry has been lowered.
+	fix(entry *heapNode[K, C])
+	// drain empties the queue and returns its entries in the order they would have been popped.
+	drain() []*heapNode[K, C]
+	Empty() bool
+	Len() int
+}
+
+// newOpenSet creates the queue of a search, with NewQueue if it is set.
+// queued is the map of the entries of the queued nodes, kept by the search.
+func (c Options[K, C]) newOpenSet(queued map[K]*heapNode[K, C], size int) openSet[K, C] {
+	if c.NewQueue == nil {
+		open := newPriorityNodes[K](c.Less, size)
+		open.fifo = c.FIFOTies
+		open.tiebreak = c.Tiebreak
+		return open
+	}
+	queue := c.NewQueue(c.Less)
+	updater, _ := queue.(QueueUpdater[K, C])
+	return &injectedQueue[K, C]{queue: queue, updater: updater, queued: queued, equal: c.equal}
+}
+
+// injectedQueue queues the entries of a search in a PriorityQueue from Options.NewQueue.
+// The order of the start nodes, Tiebreak and FIFOTies are not applied to the nodes of equal priorities.
+type injectedQueue[K comparable, C any] struct {
+	queue   PriorityQueue[K, C]
+	updater QueueUpdater[K, C]
+	queued  map[K]*heapNode[K, C]
+	equal   func(a, b C) bool
+	// len counts the queued nodes, including the outdated ones.
+	len int
+}
+
+func (q *injectedQueue[K, C]) push(entry *heapNode[K, C]) {
+	q.queue.Push(entry.Node, entry.priority)
+	q.len++
+}
+
+func (q *injectedQueue[K, C]) pop() *heapNode[K, C] {
+	node := q.queue.Pop()
+	q.len--
+	// A node is outdated once its key has been settled or queued again at another cost.
+	entry, ok := q.queued[node.Key]
+	if !ok || !q.equal(node.Cost, entry.Cost) {
+		return nil
+	}
+	return entry
+}
+
+func (q *injectedQueue[K, C]) fix(entry *heapNode[K, C]) {
+	if q.updater != nil {
+		q.updater.Update(entry.Node, entry.priority)
+		return
+	}
+	q.push(entry)
+}
+
+func (q *injectedQueue[K, C]) drain() []*heapNode[K, C] {
+	var entries []*heapNode[K, C]
+	for !q.Empty() {
+		if entry := q.pop(); entry != nil {
+			entries = append(entries, entry)
+			delete(q.queued, entry.Key)
+		}
+	}
+	return entries
+}
+
+func (q *injectedQueue[K, C]) Empty() bool {
+	return q.queue.Empty()
+}
+
+func (q *injectedQueue[K, C]) Len() int {
+	return q.len
+}
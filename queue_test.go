@@ -0,0 +1,116 @@
+package dijkstra_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+// scanQueue is a queue looking up the lowest priority by a linear scan, updating its nodes in place.
+type scanQueue struct {
+	nodes      []dijkstra.Node[Key, Cost]
+	priorities []Cost
+	updates    int
+}
+
+func (q *scanQueue) Push(node dijkstra.Node[Key, Cost], priority Cost) {
+	q.nodes = append(q.nodes, node)
+	q.priorities = append(q.priorities, priority)
+}
+
+func (q *scanQueue) Pop() dijkstra.Node[Key, Cost] {
+	i := 0
+	for j, priority := range q.priorities {
+		if priority < q.priorities[i] {
+			i = j
+		}
+	}
+	node := q.nodes[i]
+	q.nodes = slices.Delete(q.nodes, i, i+1)
+	q.priorities = slices.Delete(q.priorities, i, i+1)
+	return node
+}
+
+func (q *scanQueue) Empty() bool {
+	return len(q.nodes) == 0
+}
+
+func (q *scanQueue) Update(node dijkstra.Node[Key, Cost], priority Cost) {
+	q.updates++
+	for i := range q.nodes {
+		if q.nodes[i].Key == node.Key {
+			q.nodes[i], q.priorities[i] = node, priority
+		}
+	}
+}
+
+func TestNewQueue(t *testing.T) {
+	a := assert.New(t)
+	graph := RandomCostGraph(rand.New(rand.NewSource(7)), 12, 12, 9)
+	options := MockOptions(graph)
+	// The cost of an edge depends on both of its nodes, so that queued nodes get cheaper.
+	options.Accumulator = func(agg Cost, from, to Key) (Cost, bool) {
+		cost, ok := graph[to]
+		return agg + cost*graph[from], ok
+	}
+	start := Key{X: 0, Y: 0}
+	expected := Costs2Graph(options.Dijkstra(start, Cost(0)))
+
+	bucket := options
+	bucket.NewQueue = dijkstra.NewBucketQueue[Key, Cost]
+	costs, stats := bucket.DijkstraStats(start, Cost(0))
+	a.Equal(expected, Costs2Graph(costs))
+	a.Equal(len(costs), stats.Finalized)
+	for key := range costs {
+		path := lo.Must(bucket.ShortestPath(costs, key))
+		a.Equal(key, path[len(path)-1])
+	}
+
+	queue := &scanQueue{}
+	scan := options
+	scan.NewQueue = func(func(i, j Cost) bool) dijkstra.PriorityQueue[Key, Cost] {
+		return queue
+	}
+	costs, stats = scan.DijkstraStats(start, Cost(0))
+	a.Equal(expected, Costs2Graph(costs))
+	a.Equal(stats.Updates, queue.updates)
+	a.Positive(queue.updates)
+}
+
+func TestNewQueueSolver(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  1  1  1 
+	■  ■  3  1 
+	1  1  1  ■ 
+	1  ■  1  1 
+	`)
+	options := MockOptions(graph)
+	options.NewQueue = dijkstra.NewBucketQueue[Key, Cost]
+	solver := options.NewSolver()
+	start := Key{X: 0, Y: 0}
+	a.Equal(Costs2Graph(MockOptions(graph).Dijkstra(start, Cost(0))), Costs2Graph(solver.Run(start, Cost(0))))
+
+	delete(graph, Key{X: 1, Y: 2})
+	patched := Costs2Graph(solver.UpdateEdge(Key{X: 1, Y: 2}))
+	a.Equal(Costs2Graph(MockOptions(graph).Dijkstra(start, Cost(0))), patched)
+}
+
+func BenchmarkBucketQueue(b *testing.B) {
+	options := MockOptions(RandomCostGraph(rand.New(rand.NewSource(1)), 200, 200, 9))
+	b.Run("heap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+		}
+	})
+	options.NewQueue = dijkstra.NewBucketQueue[Key, Cost]
+	b.Run("bucket", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+		}
+	})
+}
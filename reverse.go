@@ -0,0 +1,84 @@
+package dijkstra
+
+import "context"
+
+// DijkstraReverse runs Dijkstra's algorithm backwards from the goal node over Options.ReverseEdges,
+// computing the cost from every node to the goal.
+// The accumulator is still called as (agg, from, to) in the direction of the original edge,
+// where agg is the cost from to to the goal.
+// Each Node.Prev points to the next node toward the goal,
+// so ShortestPath resolves the path from the goal back to a node.
+// When ReverseEdges is nil, the graph is assumed symmetric and Edges or WeightedEdges is used instead.
+// With ReverseEdges and WeightedEdges, the weight of each reverse edge is looked up in WeightedEdges.
+// NodePenalty is charged to the node each original edge enters, as in the forward search,
+// so the cost of a node includes the penalty of the goal but not its own.
+// EdgesCtx is not used, so it panics with ErrNoReverseEdges when neither ReverseEdges nor Edges is set.
+func (c Options[K, C]) DijkstraReverse(goal K, initial C) (costs map[K]Node[K, C]) {
+	c = c.prepare()
+	reverse, err := c.reversed()
+	if err != nil {
+		panic(err)
+	}
+	return reverse.search(goal, initial)
+}
+
+// reversed returns the options searching over the reverse edges, with the accumulators called
+// in the direction of the original edges, and NodePenalty charged to the node each edge enters.
+func (c Options[K, C]) reversed() (Options[K, C], error) {
+	reverse := c
+	reverse.EdgesCtx, reverse.edgesAt, reverse.NodePenalty = nil, nil, nil
+	// symmetric is whether the weighted edges leaving a node also enter it with the same weights.
+	symmetric := c.ReverseEdges == nil && c.WeightedEdges != nil
+	switch {
+	case c.ReverseEdges != nil:
+		reverse.Edges = c.ReverseEdges
+	case symmetric:
+		reverse.Edges = func(to K) []K {
+			edges := c.WeightedEdges(to)
+			from := make([]K, len(edges))
+			for i, edge := range edges {
+				from[i] = edge.To
+			}
+			return from
+		}
+	}
+	if reverse.Edges == nil {
+		return reverse, ErrNoReverseEdges
+	}
+	reverse.WeightedEdges = nil
+	// The reverse search enters from through the original edge from -> to, whose cost includes the penalty of to.
+	switch {
+	case c.WeightedEdges != nil:
+		reverse.AccumulatorCtx = nil
+		reverse.Accumulator = func(agg C, to, from K) (C, bool) {
+			// A symmetric graph lists the edge under the node the reverse search expands.
+			leaving, entered := from, to
+			if symmetric {
+				leaving, entered = to, from
+			}
+			for _, edge := range c.WeightedEdges(leaving) {
+				if c.canonical(edge.To) == entered {
+					return c.penalize(to, c.Add(agg, edge.Weight)), true
+				}
+			}
+			return agg, false
+		}
+	case c.AccumulatorCtx != nil:
+		reverse.AccumulatorCtx = func(ctx context.Context, agg C, to, from K) (C, bool, error) {
+			next, ok, err := c.AccumulatorCtx(ctx, agg, from, to)
+			if !ok || err != nil {
+				return next, ok, err
+			}
+			return c.penalize(to, next), true, nil
+		}
+	default:
+		reverse.Accumulator = func(agg C, to, from K) (C, bool) {
+			next, ok := c.Accumulator(agg, from, to)
+			if !ok {
+				return next, false
+			}
+			return c.penalize(to, next), true
+		}
+	}
+	return reverse, nil
+}
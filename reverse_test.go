@@ -0,0 +1,146 @@
+package dijkstra_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDijkstraReverse(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  5  1  1 
+	1  ■  9  1 
+	2  1  1  3 
+	`)
+	options := MockOptions(graph)
+	goal := Key{X: 0, Y: 3}
+	costs := options.DijkstraReverse(goal, Cost(0))
+	a.Len(costs, len(graph))
+	for key, node := range costs {
+		// The cost to the goal is the forward cost entering every node after key.
+		a.Equal(options.Dijkstra(key, Cost(0))[goal].Cost, node.Cost, key)
+		path := lo.Reverse(lo.Must(options.ShortestPath(costs, key)))
+		a.Equal(key, path[0])
+		a.Equal(goal, path[len(path)-1])
+		a.Equal(node.Cost, PathCost(graph, path))
+	}
+}
+
+func TestDijkstraReverseDirected(t *testing.T) {
+	a := assert.New(t)
+	options := WeightedOptions(map[string]map[string]int{
+		"a": {"b": 1},
+		"b": {"c": 2},
+		"c": {"a": 10},
+	})
+	options.ReverseEdges = func(to string) []string {
+		return map[string][]string{"a": {"c"}, "b": {"a"}, "c": {"b"}}[to]
+	}
+	costs := options.DijkstraReverse("c", 0)
+	a.Equal(3, costs["a"].Cost)
+	a.Equal(2, costs["b"].Cost)
+	a.Equal("b", *costs["a"].Prev)
+}
+
+func TestDijkstraReverseCtx(t *testing.T) {
+	a := assert.New(t)
+	graph := map[string]map[string]int{
+		"a": {"b": 1},
+		"b": {"c": 2},
+		"c": {"a": 10},
+	}
+	forward := WeightedOptions(graph)
+	options := dijkstra.Options[string, int]{
+		Less: forward.Less,
+		EdgesCtx: func(_ context.Context, from string) ([]string, error) {
+			return forward.Edges(from), nil
+		},
+		AccumulatorCtx: func(_ context.Context, agg int, from, to string) (int, bool, error) {
+			next, ok := forward.Accumulator(agg, from, to)
+			return next, ok, nil
+		},
+		ReverseEdges: func(to string) []string {
+			return map[string][]string{"a": {"c"}, "b": {"a"}, "c": {"b"}}[to]
+		},
+	}
+	costs := options.DijkstraReverse("c", 0)
+	a.Len(costs, 3)
+	a.Equal(3, costs["a"].Cost)
+	a.Equal(2, costs["b"].Cost)
+
+	options.ReverseEdges = nil
+	a.PanicsWithValue(dijkstra.ErrNoReverseEdges, func() {
+		options.DijkstraReverse("c", 0)
+	})
+}
+
+func TestDijkstraReverseWeighted(t *testing.T) {
+	a := assert.New(t)
+	adj := map[string][]dijkstra.Edge[string, int]{
+		"a": {{To: "b", Weight: 1}},
+		"b": {{To: "c", Weight: 2}},
+		"c": {{To: "a", Weight: 10}},
+	}
+	options := dijkstra.Options[string, int]{
+		Less: dijkstra.Ascending[int](),
+		Add:  func(agg, weight int) int { return agg + weight },
+		WeightedEdges: func(from string) []dijkstra.Edge[string, int] {
+			return adj[from]
+		},
+	}
+	options.ReverseEdges = func(to string) []string {
+		return map[string][]string{"a": {"c"}, "b": {"a"}, "c": {"b"}}[to]
+	}
+	costs := options.DijkstraReverse("c", 0)
+	a.Equal(3, costs["a"].Cost)
+	a.Equal(2, costs["b"].Cost)
+	a.Equal("b", *costs["a"].Prev)
+
+	// Without ReverseEdges, the weighted edges are assumed symmetric.
+	options.ReverseEdges = nil
+	costs = options.DijkstraReverse("c", 0)
+	a.Equal(10, costs["a"].Cost)
+	a.Equal(11, costs["b"].Cost)
+}
+
+func TestDijkstraReverseNodePenalty(t *testing.T) {
+	a := assert.New(t)
+	options := WeightedOptions(map[string]map[string]int{
+		"a": {"b": 1},
+		"b": {"c": 1},
+	})
+	options.Add = func(agg, weight int) int { return agg + weight }
+	options.NodePenalty = func(node string) int {
+		return lo.Ternary(node == "c", 10, 0)
+	}
+	options.ReverseEdges = func(to string) []string {
+		return map[string][]string{"b": {"a"}, "c": {"b"}}[to]
+	}
+	a.Equal(12, options.Dijkstra("a", 0)["c"].Cost)
+	// The penalty of the goal is charged, and the one of the start is not.
+	costs := options.DijkstraReverse("c", 0)
+	a.Equal(12, costs["a"].Cost)
+	a.Equal(11, costs["b"].Cost)
+
+	weighted := dijkstra.Options[string, int]{
+		Less: dijkstra.Ascending[int](),
+		Add:  options.Add,
+		WeightedEdges: func(from string) []dijkstra.Edge[string, int] {
+			return map[string][]dijkstra.Edge[string, int]{
+				"a": {{To: "b", Weight: 1}},
+				"b": {{To: "a", Weight: 1}, {To: "c", Weight: 1}},
+				"c": {{To: "b", Weight: 1}},
+			}[from]
+		},
+		NodePenalty: options.NodePenalty,
+	}
+	a.Equal(12, weighted.DijkstraReverse("c", 0)["a"].Cost)
+	weighted.ReverseEdges = func(to string) []string {
+		return lo.Map(weighted.WeightedEdges(to), func(edge dijkstra.Edge[string, int], _ int) string { return edge.To })
+	}
+	a.Equal(12, weighted.DijkstraReverse("c", 0)["a"].Cost)
+}
@@ -5,7 +5,7 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/naycoma/dijkstra"
+	"github.com/naycoma/dijkstra/grid"
 )
 
 type Cost uint
@@ -25,31 +25,15 @@ func main() {
 	■  1  ■  1  ■  1  1  1 
 	■  1  1  1  ■  1  ■  1 
 	`)
-	options := dijkstra.Options[Pos, Cost]{
-		Accumulator: func(agg Cost, from, to Pos) (Cost, bool) {
-			cost, ok := costMap[to]
-			return agg + cost, ok
-		},
-		Less: func(i, j Cost) bool {
-			return i < j
-		},
-		Edges: func(p Pos) (edges []Pos) {
-			for _, to := range []Pos{
-				{Y: p.Y, X: p.X + 1},
-				{Y: p.Y, X: p.X - 1},
-				{Y: p.Y + 1, X: p.X},
-				{Y: p.Y - 1, X: p.X},
-			} {
-				if _, ok := costMap[to]; ok {
-					edges = append(edges, to)
-				}
-			}
-			return edges
-		},
-	}
-
-	start := Pos{Y: 0, X: 0}
-	goal := Pos{Y: 5, X: 5}
+	options := grid.Options4(func(x, y int) bool {
+		_, ok := costMap[Pos{Y: y, X: x}]
+		return ok
+	}, func(x, y int) Cost {
+		return costMap[Pos{Y: y, X: x}]
+	})
+
+	start := grid.Point{Y: 0, X: 0}
+	goal := grid.Point{Y: 5, X: 5}
 
 	findPath := options.CreatePathFinder(start, Cost(0))
 	path, err := findPath(goal)
@@ -0,0 +1,71 @@
+package dijkstra
+
+// Search is the queue and the costs of a search, settling nodes in the order of their costs,
+// as building blocks for custom algorithms. The package-level Dijkstra function is built on it,
+// while the searches of Options run their own loop to support options such as MaxNodes or Heuristic.
+// Unlike the searches of Options, it retrieves no edges itself:
+// each node returned by Next is expanded by relaxing its edges with Relax.
+// A Search is not safe for concurrent use.
+type Search[K comparable, C any] struct {
+	less func(i, j C) bool
+	open *priorityNodes[K, C]
+	// queued holds the entry of every queued node, to lower its cost in place.
+	queued map[K]*heapNode[K, C]
+	costs  map[K]Node[K, C]
+}
+
+// NewSearch creates an empty Search ordering the costs with less.
+func NewSearch[K comparable, C any](less func(i, j C) bool) *Search[K, C] {
+	return &Search[K, C]{
+		less:   less,
+		open:   newPriorityNodes[K](less, 0),
+		queued: make(map[K]*heapNode[K, C]),
+		costs:  make(map[K]Node[K, C]),
+	}
+}
+
+// Seed queues a start node at the cost, unless it is settled or already queued at a cost that is not higher.
+func (s *Search[K, C]) Seed(key K, cost C) {
+	s.improve(Node[K, C]{Key: key, Cost: cost})
+}
+
+// Next settles the queued node with the lowest cost and returns it.
+// returns : The node, or false if no node is queued anymore.
+func (s *Search[K, C]) Next() (K, Node[K, C], bool) {
+	if s.open.Empty() {
+		var zero K
+		return zero, Node[K, C]{}, false
+	}
+	node := s.open.Pop()
+	delete(s.queued, node.Key)
+	node.Finalized = true
+	s.costs[node.Key] = node
+	return node.Key, node, true
+}
+
+// Relax queues to at the cost reached through from, unless to is settled
+// or already queued at a cost that is not higher.
+func (s *Search[K, C]) Relax(from, to K, cost C) {
+	s.improve(Node[K, C]{Key: to, Cost: cost, Prev: &from})
+}
+
+// Costs returns the settled nodes. The map is updated by Next.
+func (s *Search[K, C]) Costs() map[K]Node[K, C] {
+	return s.costs
+}
+
+func (s *Search[K, C]) improve(node Node[K, C]) {
+	if _, ok := s.costs[node.Key]; ok {
+		return
+	}
+	if entry, ok := s.queued[node.Key]; ok {
+		if s.less(node.Cost, entry.Cost) {
+			entry.Node, entry.priority = node, node.Cost
+			s.open.fix(entry)
+		}
+		return
+	}
+	entry := &heapNode[K, C]{Node: node, priority: node.Cost}
+	s.open.push(entry)
+	s.queued[node.Key] = entry
+}
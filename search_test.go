@@ -0,0 +1,46 @@
+package dijkstra_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDijkstraFunc(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(RandomCostGraph(rand.New(rand.NewSource(3)), 10, 10, 9))
+	start := Key{X: 0, Y: 0}
+	costs := dijkstra.Dijkstra(start, options.Accumulator, Cost(0), options.Less, options.Edges)
+	a.Equal(options.Dijkstra(start, Cost(0)), costs)
+}
+
+func TestSearch(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(5, 5, 1))
+	search := dijkstra.NewSearch[Key](options.Less)
+	search.Seed(Key{X: 0, Y: 0}, 0)
+	search.Seed(Key{X: 4, Y: 4}, 0)
+	search.Seed(Key{X: 4, Y: 4}, 3)
+	var order []Cost
+	for {
+		current, node, ok := search.Next()
+		if !ok {
+			break
+		}
+		a.Equal(current, node.Key)
+		a.True(node.Finalized)
+		order = append(order, node.Cost)
+		for _, dest := range options.Edges(current) {
+			search.Relax(current, dest, node.Cost+1)
+		}
+	}
+	a.Len(order, 25)
+	a.IsNonDecreasing(order)
+	costs := search.Costs()
+	a.Equal(Cost(4), costs[Key{X: 2, Y: 2}].Cost)
+	a.Equal(Cost(0), costs[Key{X: 4, Y: 4}].Cost)
+	a.Nil(costs[Key{X: 4, Y: 4}].Prev)
+	a.Equal(Key{X: 4, Y: 3}, *costs[Key{X: 4, Y: 2}].Prev)
+}
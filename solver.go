@@ -0,0 +1,229 @@
+package dijkstra
+
+import "context"
+
+// workspace holds the buffers of a search, reused across runs.
+type workspace[K comparable, C any] struct {
+	open   openSet[K, C]
+	queued map[K]*heapNode[K, C]
+	costs  map[K]Node[K, C]
+	// free holds the settled heap entries, handed out again before allocating new ones.
+	free []*heapNode[K, C]
+}
+
+// reset empties the buffers of the previous run, keeping their memory.
+func (w *workspace[K, C]) reset(c Options[K, C]) {
+	if w.open == nil {
+		w.queued = make(map[K]*heapNode[K, C])
+		w.open = c.newOpenSet(w.queued, c.SizeHint)
+		w.costs = make(map[K]Node[K, C], c.SizeHint)
+		return
+	}
+	w.clearQueue()
+	clear(w.costs)
+}
+
+// clearQueue empties the queue, keeping the costs.
+func (w *workspace[K, C]) clearQueue() {
+	for _, entry := range w.open.drain() {
+		w.release(entry)
+	}
+	clear(w.queued)
+}
+
+// seed queues the node, unless it is already queued at a cost that is not higher.
+func (w *workspace[K, C]) seed(less func(i, j C) bool, node Node[K, C]) {
+	if entry, ok := w.queued[node.Key]; ok {
+		if less(node.Cost, entry.Cost) {
+			entry.Node, entry.priority = node, node.Cost
+			w.open.fix(entry)
+		}
+		return
+	}
+	entry := w.entry()
+	entry.Node, entry.priority = node, node.Cost
+	w.open.push(entry)
+	w.queued[node.Key] = entry
+}
+
+// entry returns a heap entry to fill, reusing a released one if any.
+func (w *workspace[K, C]) entry() *heapNode[K, C] {
+	if w == nil || len(w.free) == 0 {
+		return &heapNode[K, C]{}
+	}
+	entry := w.free[len(w.free)-1]
+	w.free = w.free[:len(w.free)-1]
+	return entry
+}
+
+// release hands a heap entry that is no longer referenced back to the workspace.
+func (w *workspace[K, C]) release(entry *heapNode[K, C]) {
+	if w == nil {
+		return
+	}
+	*entry = heapNode[K, C]{}
+	w.free = append(w.free, entry)
+}
+
+// Solver runs Dijkstra's algorithm repeatedly with the same options,
+// reusing the queue, the costs map and the queue entries across runs.
+// A Solver is not safe for concurrent use.
+type Solver[K comparable, C any] struct {
+	options Options[K, C]
+	work    workspace[K, C]
+	// ran is whether start and initial hold the arguments of the last run.
+	ran     bool
+	start   K
+	initial C
+}
+
+// NewSolver creates a Solver running searches with the options.
+// It panics if the options are invalid, like Dijkstra.
+func (c Options[K, C]) NewSolver() *Solver[K, C] {
+	return &Solver[K, C]{options: c.prepare()}
+}
+
+// Run runs Dijkstra's algorithm like Options.Dijkstra.
+// The returned map is reused by the next run, so copy it to keep it.
+func (s *Solver[K, C]) Run(start K, initial C) map[K]Node[K, C] {
+	s.work.reset(s.options)
+	s.ran, s.start, s.initial = true, s.options.canonical(start), initial
+	return s.options.run(query[K, C]{starts: []K{start}, initial: initial, work: &s.work})
+}
+
+// UpdateEdge patches the costs of the last run after the costs of the edges into changed have changed,
+// such as when a cell of a grid is turned into a wall or back.
+// Only the nodes reached through changed are settled again, from the settled nodes around them,
+// and so are the nodes reached more cheaply through changed. When most of the nodes were reached
+// through changed, the search is run again instead.
+// The edges into a node are retrieved with ReverseEdges, or with WeightedEdges or Edges when the graph is symmetric.
+// Without any of them, when only EdgesCtx is set, the search is run again.
+// returns : The patched costs, the map returned by the last run. It is nil before the first run.
+func (s *Solver[K, C]) UpdateEdge(changed K) map[K]Node[K, C] {
+	if !s.ran {
+		return nil
+	}
+	c, costs := s.options, s.work.costs
+	reverse := s.reverseEdges()
+	if reverse == nil {
+		return s.Run(s.start, s.initial)
+	}
+	changed = c.canonical(changed)
+	c.begin()
+	affected := s.subtree(changed)
+	if 2*len(affected) > len(costs) {
+		return s.Run(s.start, s.initial)
+	}
+	for _, key := range affected {
+		delete(costs, key)
+	}
+	s.work.clearQueue()
+	for _, key := range affected {
+		if c.Blocked != nil && c.Blocked(key) {
+			continue
+		}
+		if key == s.start {
+			s.work.seed(c.Less, Node[K, C]{Key: key, Cost: s.initial})
+			continue
+		}
+		for _, from := range reverse(key) {
+			prev, ok := costs[from]
+			if !ok {
+				continue
+			}
+			cost, ok := s.edgeCost(prev.Cost, from, key)
+			if !ok {
+				continue
+			}
+			node := Node[K, C]{Key: key, Cost: cost, Prev: &prev.Key}
+			if c.Payload != nil {
+				node.Data = c.Payload(prev.Prev, from, key, cost)
+			}
+			s.work.seed(c.Less, node)
+		}
+	}
+	return c.run(query[K, C]{initial: s.initial, work: &s.work, repair: true})
+}
+
+// subtree lists changed and the nodes whose paths go through it in the costs of the last run.
+func (s *Solver[K, C]) subtree(changed K) []K {
+	costs := s.work.costs
+	// through holds whether the path of each node walked so far goes through changed.
+	through := map[K]bool{changed: true}
+	var chain []K
+	for key := range costs {
+		chain = chain[:0]
+		found := false
+		for current := key; ; {
+			if ok, walked := through[current]; walked {
+				found = ok
+				break
+			}
+			chain = append(chain, current)
+			node, ok := costs[current]
+			if !ok || node.Prev == nil {
+				break
+			}
+			current = *node.Prev
+		}
+		for _, k := range chain {
+			through[k] = found
+		}
+	}
+	affected := []K{changed}
+	for key, ok := range through {
+		if ok && key != changed {
+			affected = append(affected, key)
+		}
+	}
+	return affected
+}
+
+// reverseEdges returns the function retrieving the canonical nodes with edges into a node,
+// or nil if the options have no edges to retrieve them with.
+func (s *Solver[K, C]) reverseEdges() func(to K) []K {
+	c := s.options
+	switch {
+	case c.ReverseEdges != nil:
+		return func(to K) []K {
+			return c.canonicalKeys(c.ReverseEdges(to))
+		}
+	case c.WeightedEdges != nil:
+		return func(to K) []K {
+			edges := c.WeightedEdges(to)
+			froms := make([]K, len(edges))
+			for i, edge := range edges {
+				froms[i] = c.canonical(edge.To)
+			}
+			return froms
+		}
+	case c.Edges != nil:
+		return func(to K) []K {
+			return c.canonicalKeys(c.Edges(to))
+		}
+	}
+	return nil
+}
+
+// edgeCost computes the cost to reach to from from, like the search does.
+func (s *Solver[K, C]) edgeCost(agg C, from, to K) (C, bool) {
+	c := s.options
+	if c.WeightedEdges != nil {
+		for _, edge := range c.WeightedEdges(from) {
+			if c.canonical(edge.To) == to {
+				return c.penalize(to, c.Add(agg, edge.Weight)), true
+			}
+		}
+		return agg, false
+	}
+	cost, ok, err := c.accumulate(context.Background(), agg, from, to)
+	if err != nil {
+		panic(err)
+	}
+	return cost, ok
+}
+
+// Reset releases the buffers, e.g. after a run over a much larger graph than the next ones.
+func (s *Solver[K, C]) Reset() {
+	*s = Solver[K, C]{options: s.options}
+}
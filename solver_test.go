@@ -0,0 +1,146 @@
+package dijkstra_test
+
+import (
+	"context"
+	"maps"
+	"math/rand"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolver(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  1  1  1 
+	■  ■  1  1 
+	1  1  1  ■ 
+	1  ■  1  1 
+	`)
+	options := MockOptions(graph)
+	solver := options.NewSolver()
+	for _, start := range []Key{{X: 0, Y: 0}, {X: 3, Y: 3}, {X: 2, Y: 0}, {X: 0, Y: 0}} {
+		a.Equal(options.Dijkstra(start, Cost(0)), solver.Run(start, Cost(0)))
+	}
+
+	// The map of a run is reused by the next one.
+	first := maps.Clone(solver.Run(Key{X: 0, Y: 0}, Cost(0)))
+	solver.Run(Key{X: 3, Y: 3}, Cost(0))
+	solver.Reset()
+	a.Equal(first, solver.Run(Key{X: 0, Y: 0}, Cost(0)))
+}
+
+func TestSolverUpdateEdge(t *testing.T) {
+	a := assert.New(t)
+	graph := FlatGraph(12, 12, 1)
+	options := MockOptions(graph)
+	solver := options.NewSolver()
+	a.Nil(solver.UpdateEdge(Key{X: 3, Y: 3}))
+	start := Key{X: 0, Y: 0}
+	solver.Run(start, Cost(0))
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		changed := Key{X: rnd.Intn(12), Y: rnd.Intn(12)}
+		if changed == start {
+			continue
+		}
+		switch _, ok := graph[changed]; {
+		case !ok:
+			graph[changed] = Cost(rnd.Intn(3) + 1)
+		case rnd.Intn(2) == 0:
+			delete(graph, changed)
+		default:
+			graph[changed] = Cost(rnd.Intn(3) + 1)
+		}
+		costs := solver.UpdateEdge(changed)
+		a.Equal(Costs2Graph(options.Dijkstra(start, Cost(0))), Costs2Graph(costs), "changed %v", changed)
+		for key, node := range costs {
+			a.Equal(node.Cost, PathCost(graph, lo.Must(options.ShortestPath(costs, key))))
+		}
+	}
+}
+
+func TestSolverUpdateEdgeEdges(t *testing.T) {
+	a := assert.New(t)
+	graph := FlatGraph(8, 8, 1)
+	grid := MockOptions(graph)
+	weighted := dijkstra.Options[Key, Cost]{
+		Less: grid.Less,
+		Add:  func(agg, weight Cost) Cost { return agg + weight },
+		WeightedEdges: func(from Key) []dijkstra.Edge[Key, Cost] {
+			return lo.Map(grid.Edges(from), func(to Key, _ int) dijkstra.Edge[Key, Cost] {
+				return dijkstra.Edge[Key, Cost]{To: to, Weight: graph[to]}
+			})
+		},
+	}
+	withCtx := dijkstra.Options[Key, Cost]{
+		Less:        grid.Less,
+		Accumulator: grid.Accumulator,
+		EdgesCtx: func(ctx context.Context, from Key) ([]Key, error) {
+			return grid.Edges(from), nil
+		},
+	}
+	start := Key{X: 0, Y: 0}
+	for _, options := range []dijkstra.Options[Key, Cost]{weighted, withCtx} {
+		maps.Copy(graph, FlatGraph(8, 8, 1))
+		solver := options.NewSolver()
+		solver.Run(start, Cost(0))
+		rnd := rand.New(rand.NewSource(2))
+		for i := 0; i < 50; i++ {
+			changed := Key{X: rnd.Intn(8), Y: rnd.Intn(8)}
+			if changed == start {
+				continue
+			}
+			graph[changed] = Cost(rnd.Intn(3) + 1)
+			if rnd.Intn(3) == 0 {
+				delete(graph, changed)
+			}
+			a.Equal(Costs2Graph(grid.Dijkstra(start, Cost(0))), Costs2Graph(solver.UpdateEdge(changed)), "changed %v", changed)
+		}
+	}
+}
+
+func TestSolverUpdateEdgeLocal(t *testing.T) {
+	a := assert.New(t)
+	graph := FlatGraph(20, 20, 1)
+	options := MockOptions(graph)
+	edges := options.Edges
+	expanded := 0
+	options.Edges = func(p Key) []Key {
+		expanded++
+		return edges(p)
+	}
+	solver := options.NewSolver()
+	solver.Run(Key{X: 0, Y: 0}, Cost(0))
+	expanded = 0
+	// Only the nodes beyond the corner cell are reached through it.
+	graph[Key{X: 18, Y: 18}] = 5
+	costs := solver.UpdateEdge(Key{X: 18, Y: 18})
+	a.Equal(Cost(40), costs[Key{X: 18, Y: 18}].Cost)
+	a.Equal(Cost(38), costs[Key{X: 19, Y: 19}].Cost)
+	a.Less(expanded, 20)
+}
+
+func BenchmarkSolver(b *testing.B) {
+	options := MockOptions(FlatGraph(30, 30, 1))
+	starts := []Key{{X: 0, Y: 0}, {X: 15, Y: 15}, {X: 29, Y: 0}}
+	b.Run("Dijkstra", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 1000; j++ {
+				options.Dijkstra(starts[j%len(starts)], Cost(0))
+			}
+		}
+	})
+	b.Run("Solver", func(b *testing.B) {
+		b.ReportAllocs()
+		solver := options.NewSolver()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 1000; j++ {
+				solver.Run(starts[j%len(starts)], Cost(0))
+			}
+		}
+	})
+}
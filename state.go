@@ -0,0 +1,71 @@
+package dijkstra
+
+// StateKey is a node of a state-augmented graph: a node of the base graph
+// together with the state it was reached in, such as the time of day or a ticket held.
+// The same base node may be reached in several states, each with its own cost and path.
+type StateKey[K comparable, S comparable] struct {
+	Key   K
+	State S
+}
+
+// WithState creates options for a graph whose edge costs depend on the state a node was reached in,
+// e.g. time-dependent costs, without encoding the state into the key by hand.
+// edges : Function to retrieve the nodes adjacent to a node of the base graph.
+// transition : Function to compute the state and the cost of reaching to from from, reached in fromState at agg,
+// or false if the edge is impassable.
+// less : Comparison function to determine the order of costs.
+// The states of the edges leaving a node are computed from the cost the node is reached at,
+// so Reachable runs the search, and the searches over reverse edges need ReverseEdges
+// and transitions that do not depend on the accumulated cost.
+// Start the search from StateKey{Key: start, State: initialState}, and resolve the paths with StatePath.
+func WithState[K comparable, S comparable, C any](
+	edges func(from K) []K,
+	transition func(agg C, from K, fromState S, to K) (toState S, next C, ok bool),
+	less func(i, j C) bool,
+) Options[StateKey[K, S], C] {
+	return Options[StateKey[K, S], C]{
+		Less: less,
+		edgesAt: func(from StateKey[K, S], agg C) []StateKey[K, S] {
+			var dest []StateKey[K, S]
+			for _, to := range edges(from.Key) {
+				if state, _, ok := transition(agg, from.Key, from.State, to); ok {
+					dest = append(dest, StateKey[K, S]{Key: to, State: state})
+				}
+			}
+			return dest
+		},
+		Accumulator: func(agg C, from, to StateKey[K, S]) (C, bool) {
+			state, next, ok := transition(agg, from.Key, from.State, to.Key)
+			return next, ok && state == to.State
+		},
+	}
+}
+
+// StatePath resolves the path to the goal node of the base graph with PathResolve,
+// through the cheapest of the states the goal was reached in.
+// returns : The nodes of the base graph along the path, or NotReachableError if the goal was reached in no state.
+func StatePath[K comparable, S comparable, C any](
+	c Options[StateKey[K, S], C],
+	costs map[StateKey[K, S]]Node[StateKey[K, S], C],
+	goal K,
+) ([]K, error) {
+	var best StateKey[K, S]
+	found := false
+	for key, node := range costs {
+		if key.Key == goal && (!found || c.Less(node.Cost, costs[best].Cost)) {
+			best, found = key, true
+		}
+	}
+	if !found {
+		return nil, newNotReachableError(costs, c.Less, StateKey[K, S]{Key: goal})
+	}
+	path, err := c.PathResolve(costs, best)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]K, len(path))
+	for i, key := range path {
+		keys[i] = key.Key
+	}
+	return keys, nil
+}
@@ -0,0 +1,94 @@
+package dijkstra_test
+
+import (
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithState(t *testing.T) {
+	a := assert.New(t)
+	adj := map[string][]string{
+		"a": {"b"},
+		"b": {"a", "c", "s"},
+		"c": {"b", "d"},
+		"d": {"c"},
+		"s": {"b"},
+	}
+	// Edges cost 4 until the switch s is visited, and 1 afterwards.
+	options := dijkstra.WithState(func(from string) []string {
+		return adj[from]
+	}, func(agg int, from string, switched bool, to string) (bool, int, bool) {
+		cost := 4
+		if switched {
+			cost = 1
+		}
+		return switched || to == "s", agg + cost, true
+	}, dijkstra.Ascending[int]())
+
+	start := dijkstra.StateKey[string, bool]{Key: "a"}
+	costs := options.Dijkstra(start, 0)
+	a.Equal(12, costs[dijkstra.StateKey[string, bool]{Key: "d"}].Cost)
+	a.Equal(11, costs[dijkstra.StateKey[string, bool]{Key: "d", State: true}].Cost)
+	// The path goes back through b once switched.
+	path, err := dijkstra.StatePath(options, costs, "d")
+	a.NoError(err)
+	a.Equal([]string{"a", "b", "s", "b", "c", "d"}, path)
+
+	_, err = dijkstra.StatePath(options, costs, "z")
+	var notReachableErr *dijkstra.NotReachableError[dijkstra.StateKey[string, bool], int]
+	a.ErrorAs(err, &notReachableErr)
+}
+
+func TestWithStateSearches(t *testing.T) {
+	a := assert.New(t)
+	adj := map[string][]string{
+		"a": {"b"},
+		"b": {"a", "c", "s"},
+		"c": {"b", "d"},
+		"d": {"c"},
+		"s": {"b"},
+	}
+	transition := func(agg int, from string, switched bool, to string) (bool, int, bool) {
+		cost := 4
+		if switched {
+			cost = 1
+		}
+		return switched || to == "s", agg + cost, true
+	}
+	options := dijkstra.WithState(func(from string) []string {
+		return adj[from]
+	}, transition, dijkstra.Ascending[int]())
+	type key = dijkstra.StateKey[string, bool]
+	finalized := 0
+	options.OnFinalize = func(key key, node dijkstra.Node[key, int]) {
+		finalized++
+	}
+	start, goal := key{Key: "a"}, key{Key: "d", State: true}
+	costs := options.Dijkstra(start, 0)
+	a.Equal(len(costs), finalized)
+	a.ElementsMatch(lo.Keys(costs), lo.Keys(options.Reachable(start)))
+
+	options.OnFinalize = nil
+	nodes := lo.Keys(costs)
+	a.Equal(options.AllPairs(nodes, 0), options.AllPairsParallel(nodes, 0, 4))
+
+	// The transitions do not depend on the accumulated cost, so the graph can be searched backwards.
+	options.ReverseEdges = func(to key) []key {
+		var from []key
+		for _, prev := range adj[to.Key] {
+			for _, switched := range []bool{false, true} {
+				if state, _, _ := transition(0, prev, switched, to.Key); state == to.State {
+					from = append(from, key{Key: prev, State: switched})
+				}
+			}
+		}
+		return from
+	}
+	a.Equal(costs[goal].Cost, options.DijkstraReverse(goal, 0)[start].Cost)
+	_, cost, err := options.BidirectionalAStar(start, goal, 0, func(a, b key) int { return 0 }, func(g, h int) int { return g + h })
+	a.NoError(err)
+	a.Equal(costs[goal].Cost, cost)
+}
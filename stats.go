@@ -0,0 +1,60 @@
+package dijkstra
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// DistanceStats summarizes the distribution of the costs of the settled nodes.
+// toFloat : Function to convert a cost to a float.
+// excludeStart : Whether to leave out the nodes without a predecessor, such as the start node.
+// returns : The order statistics of the costs, interpolated between the closest ranks,
+// and the number of nodes considered. All statistics are zero if no node is considered.
+func DistanceStats[K comparable, C any](
+	costs map[K]Node[K, C],
+	toFloat func(C) float64,
+	excludeStart bool,
+) (min, median, p90, max float64, n int) {
+	values := make([]float64, 0, len(costs))
+	for _, node := range costs {
+		if excludeStart && node.Prev == nil {
+			continue
+		}
+		values = append(values, toFloat(node.Cost))
+	}
+	if len(values) == 0 {
+		return 0, 0, 0, 0, 0
+	}
+	sort.Float64s(values)
+	return values[0], percentile(values, 0.5), percentile(values, 0.9), values[len(values)-1], len(values)
+}
+
+// percentile interpolates the p-th quantile of values sorted in ascending order.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// SortedByCost lists the settled nodes in ascending order of cost, without modifying the costs.
+// Nodes with equal costs are ordered by their keys formatted with fmt.Sprint, so the order is reproducible.
+func SortedByCost[K comparable, C any](costs map[K]Node[K, C], less func(i, j C) bool) []Node[K, C] {
+	nodes := make([]Node[K, C], 0, len(costs))
+	ids := make(map[K]string, len(costs))
+	for key, node := range costs {
+		nodes = append(nodes, node)
+		ids[key] = fmt.Sprint(key)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if less(nodes[i].Cost, nodes[j].Cost) {
+			return true
+		}
+		if less(nodes[j].Cost, nodes[i].Cost) {
+			return false
+		}
+		return ids[nodes[i].Key] < ids[nodes[j].Key]
+	})
+	return nodes
+}
@@ -0,0 +1,49 @@
+package dijkstra_test
+
+import (
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceStats(t *testing.T) {
+	a := assert.New(t)
+	// A corridor of 11 cells has costs 0 to 10 from one end.
+	options := MockOptions(FlatGraph(11, 1, 1))
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	toFloat := func(c Cost) float64 { return float64(c) }
+
+	min, median, p90, max, n := dijkstra.DistanceStats(costs, toFloat, false)
+	a.Equal(11, n)
+	a.Equal(0.0, min)
+	a.Equal(5.0, median)
+	a.Equal(9.0, p90)
+	a.Equal(10.0, max)
+
+	min, median, _, max, n = dijkstra.DistanceStats(costs, toFloat, true)
+	a.Equal(10, n)
+	a.Equal(1.0, min)
+	a.Equal(5.5, median)
+	a.Equal(10.0, max)
+
+	_, _, _, _, n = dijkstra.DistanceStats(map[Key]dijkstra.Node[Key, Cost]{}, toFloat, false)
+	a.Zero(n)
+}
+
+func TestSortedByCost(t *testing.T) {
+	a := assert.New(t)
+	options := MockOptions(FlatGraph(5, 4, 1))
+	costs := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	nodes := dijkstra.SortedByCost(costs, options.Less)
+	a.Len(nodes, 20)
+	a.Len(costs, 20)
+	for i, node := range nodes {
+		// On a flat grid, the costs are the BFS layers: the Manhattan distances.
+		a.Equal(Cost(node.Key.X+node.Key.Y), node.Cost)
+		if i > 0 {
+			a.LessOrEqual(nodes[i-1].Cost, node.Cost)
+		}
+	}
+	a.Equal(nodes, dijkstra.SortedByCost(costs, options.Less))
+}
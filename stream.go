@@ -0,0 +1,39 @@
+package dijkstra
+
+import (
+	"iter"
+	"slices"
+)
+
+// Edge is an edge to a node with its weight.
+type Edge[K comparable, C any] struct {
+	To     K
+	Weight C
+}
+
+// DijkstraFromEdgeStream runs Dijkstra's algorithm over edges yielded lazily,
+// without allocating a slice for the neighbors of each node.
+// initial : The initial cost to reach the start node.
+// less : Comparison function to determine the order of costs.
+// add : Function to add the weight of an edge to the accumulated cost.
+// edges : Function to yield the edges leaving a node.
+// returns : The costs to reach each node from the start node.
+func DijkstraFromEdgeStream[K comparable, C any](
+	start K,
+	initial C,
+	less func(i C, j C) bool,
+	add func(agg C, weight C) C,
+	edges func(from K) iter.Seq[Edge[K, C]],
+) (costs map[K]Node[K, C]) {
+	// The edges of each node are collected into the same buffer, which the search is done with
+	// before it retrieves the edges of the next node.
+	var buffer []Edge[K, C]
+	return Options[K, C]{
+		Less: less,
+		Add:  add,
+		WeightedEdges: func(from K) []Edge[K, C] {
+			buffer = slices.AppendSeq(buffer[:0], edges(from))
+			return buffer
+		},
+	}.search(start, initial)
+}
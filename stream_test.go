@@ -0,0 +1,61 @@
+package dijkstra_test
+
+import (
+	"fmt"
+	"iter"
+	"math/rand"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleDijkstraFromEdgeStream() {
+	less := func(i, j int) bool { return i < j }
+	add := func(agg, weight int) int { return agg + weight }
+	// Each number leads to its successor and its double, generated on demand.
+	edges := func(from int) iter.Seq[dijkstra.Edge[int, int]] {
+		return func(yield func(dijkstra.Edge[int, int]) bool) {
+			for _, to := range []int{from + 1, from * 2} {
+				if to > 100 {
+					continue
+				}
+				if !yield(dijkstra.Edge[int, int]{To: to, Weight: 1}) {
+					return
+				}
+			}
+		}
+	}
+	costs := dijkstra.DijkstraFromEdgeStream(1, 0, less, add, edges)
+	path, _ := dijkstra.Options[int, int]{Less: less}.ShortestPath(costs, 10)
+	fmt.Println(path, costs[10].Cost)
+	// Output: [1 2 4 5 10] 4
+}
+
+func TestDijkstraFromEdgeStream(t *testing.T) {
+	a := assert.New(t)
+	graph := Text2Graph(`
+	1  ■  1  1 
+	1  1  1  ■ 
+	■  3  1  1 
+	`)
+	options := MockOptions(graph)
+	edges := func(from Key) iter.Seq[dijkstra.Edge[Key, Cost]] {
+		return func(yield func(dijkstra.Edge[Key, Cost]) bool) {
+			for _, to := range options.Edges(from) {
+				if !yield(dijkstra.Edge[Key, Cost]{To: to, Weight: graph[to]}) {
+					return
+				}
+			}
+		}
+	}
+	add := func(agg, weight Cost) Cost { return agg + weight }
+	expected := options.Dijkstra(Key{X: 0, Y: 0}, Cost(0))
+	costs := dijkstra.DijkstraFromEdgeStream(Key{X: 0, Y: 0}, Cost(0), options.Less, add, edges)
+	a.Equal(Costs2Graph(expected), Costs2Graph(costs))
+
+	// The streamed edges run through the same search, so the paths break ties alike.
+	graph = RandomCostGraph(rand.New(rand.NewSource(1)), 10, 10, 3)
+	options = MockOptions(graph)
+	a.Equal(options.Dijkstra(Key{X: 0, Y: 0}, Cost(0)), dijkstra.DijkstraFromEdgeStream(Key{X: 0, Y: 0}, Cost(0), options.Less, add, edges))
+}
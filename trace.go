@@ -0,0 +1,47 @@
+package dijkstra
+
+// EventKind is the kind of a step of a search recorded by DijkstraTrace.
+type EventKind string
+
+const (
+	// EventSeed is a start node queued at the initial cost.
+	EventSeed EventKind = "seed"
+	// EventPop is the queued node with the lowest cost taken from the queue.
+	EventPop EventKind = "pop"
+	// EventFinalize is the popped node settled at its cost.
+	EventFinalize EventKind = "finalize"
+	// EventRelax is the cost computed for an edge, and whether it was kept.
+	EventRelax EventKind = "relax"
+	// EventSkipStale is an edge into a settled node, skipped before computing its cost.
+	EventSkipStale EventKind = "skip_stale"
+)
+
+// Event is a step of a search recorded by DijkstraTrace.
+type Event[K comparable, C any] struct {
+	Kind EventKind `json:"kind"`
+	// Key is the node of the step, the node the edge leads to for EventRelax and EventSkipStale.
+	Key K `json:"key"`
+	// From is the node the edge leaves for EventRelax and EventSkipStale, and nil otherwise.
+	From *K `json:"from,omitempty"`
+	// Cost is the cost of the node, or the cost computed for the edge for EventRelax.
+	// It is the zero value for EventSkipStale.
+	Cost C `json:"cost"`
+	// Accepted is whether the cost of EventRelax was kept to settle the node with.
+	Accepted bool `json:"accepted,omitempty"`
+}
+
+// DijkstraTrace runs Dijkstra's algorithm like Dijkstra, also recording each of its steps in order,
+// e.g. to save the search as JSON and replay it in a visualizer.
+// The trace is the same for the same graph as long as Edges lists the nodes in the same order,
+// and set Tiebreak to make it independent of that order.
+func (c Options[K, C]) DijkstraTrace(start K, initial C) (costs map[K]Node[K, C], events []Event[K, C]) {
+	costs = c.prepare().run(query[K, C]{starts: []K{start}, initial: initial, trace: &events})
+	return costs, events
+}
+
+// record appends the event to the trace, if one is requested.
+func (q query[K, C]) record(event Event[K, C]) {
+	if q.trace != nil {
+		*q.trace = append(*q.trace, event)
+	}
+}
@@ -0,0 +1,48 @@
+package dijkstra_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/naycoma/dijkstra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDijkstraTrace(t *testing.T) {
+	a := assert.New(t)
+	options := dijkstra.StringGraphOptions(map[string]map[string]float64{
+		"a": {"b": 1, "c": 4},
+		"b": {"a": 1, "c": 2},
+	})
+	costs, events := options.DijkstraTrace("a", 0)
+	a.Equal(options.Dijkstra("a", 0), costs)
+	from := func(key string) *string {
+		return &key
+	}
+	a.Equal([]dijkstra.Event[string, float64]{
+		{Kind: dijkstra.EventSeed, Key: "a", Cost: 0},
+		{Kind: dijkstra.EventPop, Key: "a", Cost: 0},
+		{Kind: dijkstra.EventFinalize, Key: "a", Cost: 0},
+		{Kind: dijkstra.EventRelax, Key: "b", From: from("a"), Cost: 1, Accepted: true},
+		{Kind: dijkstra.EventRelax, Key: "c", From: from("a"), Cost: 4, Accepted: true},
+		{Kind: dijkstra.EventPop, Key: "b", Cost: 1},
+		{Kind: dijkstra.EventFinalize, Key: "b", Cost: 1},
+		{Kind: dijkstra.EventSkipStale, Key: "a", From: from("b")},
+		{Kind: dijkstra.EventRelax, Key: "c", From: from("b"), Cost: 3, Accepted: true},
+		{Kind: dijkstra.EventPop, Key: "c", Cost: 3},
+		{Kind: dijkstra.EventFinalize, Key: "c", Cost: 3},
+	}, events)
+
+	_, again := options.DijkstraTrace("a", 0)
+	a.Equal(events, again)
+
+	data, err := json.Marshal(events[3:5])
+	a.NoError(err)
+	a.JSONEq(`[
+		{"kind": "relax", "key": "b", "from": "a", "cost": 1, "accepted": true},
+		{"kind": "relax", "key": "c", "from": "a", "cost": 4, "accepted": true}
+	]`, string(data))
+	var decoded []dijkstra.Event[string, float64]
+	a.NoError(json.Unmarshal(data, &decoded))
+	a.Equal(events[3:5], decoded)
+}